@@ -0,0 +1,329 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// apiServer serves the status of the clusters as JSON and HTML; the status is computed on every request,
+// reading objects from the informer cache.
+type apiServer struct {
+	client          client.Client
+	namespace       string
+	stuckThresholds *status.StuckThresholds
+}
+
+// clusterSummary is the summary of the status of a cluster, as returned by the fleet endpoint.
+type clusterSummary struct {
+	Namespace string               `json:"namespace"`
+	Name      string               `json:"name"`
+	Ready     *clusterv1.Condition `json:"ready,omitempty"`
+	Failure   *status.Failure      `json:"failure,omitempty"`
+	Deleted   bool                 `json:"deleted,omitempty"`
+	Stuck     bool                 `json:"stuck,omitempty"`
+	Paused    bool                 `json:"paused,omitempty"`
+	Machines  machinesSummary      `json:"machines"`
+	Error     string               `json:"error,omitempty"`
+}
+
+// clusterView is the data for the cluster HTML view.
+type clusterView struct {
+	Tree   *status.TreeNode
+	Paused bool
+}
+
+// machinesSummary is the number of machines in a cluster, and the number of ready machines.
+type machinesSummary struct {
+	Total int `json:"total"`
+	Ready int `json:"ready"`
+}
+
+// register registers the handlers for the API endpoints and the HTML views:
+// - /api/v1/clusters returns the fleet summary as JSON.
+// - /api/v1/clusters/<namespace>/<name> returns the object tree for a cluster as JSON.
+// - / and /clusters/<namespace>/<name> return the same information as HTML.
+// The object tree endpoints support the disableNoEcho, disableGrouping, byFailureDomain, onlyUnhealthy and filter
+// query parameters, with the same semantic of the corresponding CLI flags.
+func (s *apiServer) register(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/clusters", s.handleFleetJSON)
+	mux.HandleFunc("/api/v1/clusters/", s.handleClusterJSON)
+	mux.HandleFunc("/clusters/", s.handleClusterHTML)
+	mux.HandleFunc("/", s.handleFleetHTML)
+}
+
+func (s *apiServer) handleFleetJSON(w http.ResponseWriter, r *http.Request) {
+	summaries, err := s.getFleetSummary(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, summaries)
+}
+
+func (s *apiServer) handleClusterJSON(w http.ResponseWriter, r *http.Request) {
+	objs, code, err := s.getClusterTree(r.Context(), strings.TrimPrefix(r.URL.Path, "/api/v1/clusters/"), r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeJSON(w, objs.ToTreeNode())
+}
+
+func (s *apiServer) handleFleetHTML(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	summaries, err := s.getFleetSummary(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeHTML(w, fleetTemplate, summaries)
+}
+
+func (s *apiServer) handleClusterHTML(w http.ResponseWriter, r *http.Request) {
+	objs, code, err := s.getClusterTree(r.Context(), strings.TrimPrefix(r.URL.Path, "/clusters/"), r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), code)
+		return
+	}
+	writeHTML(w, clusterTemplate, clusterView{
+		Tree:   objs.ToTreeNode(),
+		Paused: objs.IsPaused(),
+	})
+}
+
+// getClusterTree returns the object tree for the cluster identified by a <namespace>/<name> path, applying the
+// options in the query; in case of error, the HTTP status code for the error is returned.
+func (s *apiServer) getClusterTree(ctx context.Context, path string, query url.Values) (*status.ObjectTree, int, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, http.StatusNotFound, fmt.Errorf("invalid cluster path %q, the path must be in the form <namespace>/<name>", path)
+	}
+	if s.namespace != "" && parts[0] != s.namespace {
+		return nil, http.StatusNotFound, fmt.Errorf("cluster %s/%s not found", parts[0], parts[1])
+	}
+
+	options := status.DiscoverOptions{
+		StuckThresholds: s.stuckThresholds,
+	}
+	var onlyUnhealthy bool
+	for param, target := range map[string]*bool{
+		"disableNoEcho":   &options.DisableNoEcho,
+		"disableGrouping": &options.DisableGroupObjects,
+		"byFailureDomain": &options.OrganizeByFailureDomain,
+		"onlyUnhealthy":   &onlyUnhealthy,
+	} {
+		if v := query.Get(param); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, http.StatusBadRequest, fmt.Errorf("invalid value %q for %s", v, param)
+			}
+			*target = b
+		}
+	}
+	filters, err := parseFilters(query["filter"])
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	objs, err := discoverCluster(ctx, s.client, client.ObjectKey{Namespace: parts[0], Name: parts[1]}, options, filters, onlyUnhealthy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, http.StatusNotFound, err
+		}
+		return nil, http.StatusInternalServerError, err
+	}
+	return objs, http.StatusOK, nil
+}
+
+// getFleetSummary returns the summary of the status of all the clusters.
+// NB. If discovery fails for a cluster, the error is reported in the cluster summary.
+func (s *apiServer) getFleetSummary(ctx context.Context) ([]clusterSummary, error) {
+	clusters, err := listClusters(ctx, s.client, s.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []clusterSummary{}
+	for _, cluster := range clusters {
+		summary := clusterSummary{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name,
+		}
+		objs, err := status.Discovery(ctx, s.client, cluster, status.DiscoverOptions{
+			StuckThresholds: s.stuckThresholds,
+		})
+		if err != nil {
+			summary.Error = err.Error()
+			summaries = append(summaries, summary)
+			continue
+		}
+
+		root := objs.GetRoot()
+		summary.Ready = root.GetReadyCondition()
+		summary.Failure = root.Failure
+		summary.Deleted = root.IsDeleted()
+		summary.Stuck = root.IsStuck()
+		summary.Paused = objs.IsPaused()
+		objs.Walk(func(node *status.Node, _ int) bool {
+			if node.Kind != "Machine" {
+				return true
+			}
+			items := 1
+			if node.IsGroup() {
+				items = len(node.GroupItems)
+			}
+			summary.Machines.Total += items
+			if ready := node.GetReadyCondition(); ready != nil && ready.Status == corev1.ConditionTrue {
+				summary.Machines.Ready += items
+			}
+			return true
+		})
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeHTML(w http.ResponseWriter, t *template.Template, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := t.Execute(w, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+var templateFuncs = template.FuncMap{
+	"ready": func(conditions clusterv1.Conditions) *clusterv1.Condition {
+		for i := range conditions {
+			if conditions[i].Type == clusterv1.ReadyCondition {
+				return &conditions[i]
+			}
+		}
+		return nil
+	},
+	"class": func(c *clusterv1.Condition) string {
+		if c == nil {
+			return "none"
+		}
+		if c.Status == corev1.ConditionTrue {
+			return "true"
+		}
+		switch c.Severity {
+		case clusterv1.ConditionSeverityError:
+			return "error"
+		case clusterv1.ConditionSeverityWarning:
+			return "warning"
+		default:
+			return "info"
+		}
+	},
+	"since": func(t metav1.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return duration.HumanDuration(time.Since(t.Time))
+	},
+	"isStuck": func(stuck []clusterv1.ConditionType, t clusterv1.ConditionType) bool {
+		for _, s := range stuck {
+			if s == t {
+				return true
+			}
+		}
+		return false
+	},
+}
+
+const htmlStyle = `<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+td, th { padding: 2px 8px; text-align: left; vertical-align: top; }
+ul { list-style: none; padding-left: 20px; }
+.true { color: green; } .error { color: red; } .warning { color: darkorange; } .info { color: black; } .none { color: gray; }
+.marker { font-weight: bold; color: red; } .stuck { font-weight: bold; color: darkorange; } .paused { font-weight: bold; color: darkcyan; }
+.details { color: gray; }
+</style>`
+
+var fleetTemplate = template.Must(template.New("fleet").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html><head><title>Clusters</title>` + htmlStyle + `</head><body>
+<h1>Clusters</h1>
+<table>
+<tr><th>NAMESPACE</th><th>NAME</th><th>READY</th><th>SEVERITY</th><th>REASON</th><th>SINCE</th><th>MACHINES</th><th>MESSAGE</th></tr>
+{{- range . }}
+<tr class="{{ class .Ready }}">
+<td>{{ .Namespace }}</td>
+<td><a href="/clusters/{{ .Namespace }}/{{ .Name }}">{{ .Name }}</a>
+{{- if .Failure }} <span class="marker">FAILED</span>{{ end }}
+{{- if .Deleted }} <span class="marker">DELETED</span>{{ end }}
+{{- if .Stuck }} <span class="stuck">STUCK</span>{{ end }}
+{{- if .Paused }} <span class="paused">PAUSED</span>{{ end }}</td>
+{{- if .Ready }}
+<td>{{ .Ready.Status }}</td><td>{{ .Ready.Severity }}</td><td>{{ .Ready.Reason }}</td><td>{{ since .Ready.LastTransitionTime }}</td>
+{{- else }}
+<td></td><td></td><td></td><td></td>
+{{- end }}
+<td>{{ .Machines.Ready }}/{{ .Machines.Total }}</td>
+<td>{{ if .Error }}{{ .Error }}{{ else if .Ready }}{{ .Ready.Message }}{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</body></html>
+`))
+
+var clusterTemplate = template.Must(template.New("cluster").Funcs(templateFuncs).Parse(`<!DOCTYPE html>
+<html><head><title>{{ .Tree.Namespace }}/{{ .Tree.Name }}</title>` + htmlStyle + `</head><body>
+<h1><a href="/">Clusters</a> / {{ .Tree.Namespace }}/{{ .Tree.Name }}</h1>
+{{- if .Paused }}
+<p class="paused">PAUSED: reconciliation of the cluster and of all its objects is paused, conditions might be out of date.</p>
+{{- end }}
+<ul>{{ template "node" .Tree }}</ul>
+</body></html>
+
+{{- define "node" }}
+{{- $ready := ready .Conditions }}
+<li>
+<span class="{{ class $ready }}">
+{{- if .Failure }}<span class="marker">FAILED</span> {{ end }}
+{{- if .Deleted }}<span class="marker">DELETED</span> {{ end }}
+{{- if .Stale }}<span class="stuck">STALE</span> {{ end }}
+{{- if .Paused }}<span class="paused">PAUSED</span> {{ end }}
+{{- if isStuck .StuckConditions "Ready" }}<span class="stuck">STUCK</span> {{ end }}
+{{- if .GroupItems }}<b>{{ len .GroupItems }} {{ .Kind }}s</b>
+{{- else if .FailureDomainStats }}{{ .Kind }}/<b>{{ .Name }}</b> ({{ .FailureDomainStats.Ready }}/{{ .FailureDomainStats.Total }} ready)
+{{- else if .Virtual }}{{ .Name }}
+{{- else }}{{ if .MetaName }}{{ .MetaName }} - {{ end }}{{ .Kind }}/<b>{{ .Name }}</b>{{ end }}
+{{- if $ready }} &mdash; {{ $ready.Status }} {{ $ready.Severity }} {{ $ready.Reason }} ({{ since $ready.LastTransitionTime }}) {{ $ready.Message }}{{ end }}
+</span>
+{{- if .GroupItems }}<div class="details">{{ range $i, $item := .GroupItems }}{{ if $i }}, {{ end }}{{ $item }}{{ end }}</div>{{ end }}
+{{- if .Failure }}<div class="marker">{{ .Failure.Reason }}: {{ .Failure.Message }}</div>{{ end }}
+{{- range .Inconsistencies }}<div class="stuck">Inconsistency: {{ . }}</div>{{ end }}
+{{- if .Children }}
+<ul>{{ range .Children }}{{ template "node" . }}{{ end }}</ul>
+{{- end }}
+</li>
+{{- end }}
+`))
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newTestAPIServer returns the handler for an API server reading the objects for a cluster named cluster.
+func newTestAPIServer(namespace string) http.Handler {
+	s := &apiServer{
+		client:    fake.NewFakeClientWithScheme(Scheme, testClusterObjects(testCluster("cluster"))...),
+		namespace: namespace,
+	}
+	mux := http.NewServeMux()
+	s.register(mux)
+	return mux
+}
+
+func Test_apiServerClusterTree(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		url       string
+		wantCode  int
+		wantNames []string
+	}{
+		{
+			name:      "Cluster tree",
+			url:       "/api/v1/clusters/ns/cluster",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"cluster", "Workers", "cluster-md", "cluster-m3", "cluster-m1,cluster-m2"},
+		},
+		{
+			name:      "Cluster tree with grouping disabled",
+			url:       "/api/v1/clusters/ns/cluster?disableGrouping=true",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"cluster", "Workers", "cluster-md", "cluster-m1", "cluster-m2", "cluster-m3"},
+		},
+		{
+			name:      "Cluster tree with only unhealthy objects",
+			url:       "/api/v1/clusters/ns/cluster?onlyUnhealthy=true",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"cluster", "Workers", "cluster-md", "cluster-m3"},
+		},
+		{
+			name:      "Cluster tree with filters",
+			url:       "/api/v1/clusters/ns/cluster?disableGrouping=true&filter=name=cluster-m1&filter=name=cluster-m2",
+			wantCode:  http.StatusOK,
+			wantNames: []string{"cluster", "Workers", "cluster-md", "cluster-m1", "cluster-m2"},
+		},
+		{
+			name:     "Invalid path",
+			url:      "/api/v1/clusters/ns",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:      "Cluster in a namespace not served",
+			namespace: "other",
+			url:       "/api/v1/clusters/ns/cluster",
+			wantCode:  http.StatusNotFound,
+		},
+		{
+			name:     "Cluster not found",
+			url:      "/api/v1/clusters/ns/missing",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Invalid boolean query parameter",
+			url:      "/api/v1/clusters/ns/cluster?onlyUnhealthy=maybe",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Invalid filter",
+			url:      "/api/v1/clusters/ns/cluster?filter=,",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			w := httptest.NewRecorder()
+			newTestAPIServer(tt.namespace).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			g.Expect(w.Code).To(Equal(tt.wantCode), w.Body.String())
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			root := &status.TreeNode{}
+			g.Expect(json.Unmarshal(w.Body.Bytes(), root)).To(Succeed())
+			// NB. Group nodes are identified by their items.
+			var names []string
+			var walk func(node *status.TreeNode)
+			walk = func(node *status.TreeNode) {
+				name := node.Name
+				if len(node.GroupItems) > 0 {
+					name = strings.Join(node.GroupItems, ",")
+				}
+				names = append(names, name)
+				for _, child := range node.Children {
+					walk(child)
+				}
+			}
+			walk(root)
+			g.Expect(names).To(Equal(tt.wantNames))
+		})
+	}
+}
+
+func Test_apiServerFleetSummary(t *testing.T) {
+	g := NewWithT(t)
+
+	var objects []runtime.Object
+	objects = append(objects, testClusterObjects(testCluster("cluster1"))...)
+	objects = append(objects, testClusterObjects(testCluster("cluster2"))...)
+	s := &apiServer{
+		client: fake.NewFakeClientWithScheme(Scheme, objects...),
+	}
+	mux := http.NewServeMux()
+	s.register(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/clusters", nil))
+	g.Expect(w.Code).To(Equal(http.StatusOK), w.Body.String())
+
+	var summaries []clusterSummary
+	g.Expect(json.Unmarshal(w.Body.Bytes(), &summaries)).To(Succeed())
+	g.Expect(summaries).To(HaveLen(2))
+	for i, name := range []string{"cluster1", "cluster2"} {
+		g.Expect(summaries[i].Name).To(Equal(name))
+		g.Expect(summaries[i].Error).To(BeEmpty())
+		g.Expect(summaries[i].Ready).ToNot(BeNil())
+		// NB. The two ready machines are grouped, and they are counted for the items in the group.
+		g.Expect(summaries[i].Machines).To(Equal(machinesSummary{Total: 3, Ready: 2}))
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// discoverCluster fetches the Cluster with the given key and returns its object tree, keeping only the objects
+// matching at least one of the filters, if any, and only the unhealthy objects, if onlyUnhealthy is set.
+// NB. Errors fetching the Cluster are returned as is, so callers can check if the Cluster was not found.
+func discoverCluster(ctx context.Context, c client.Client, key client.ObjectKey, options status.DiscoverOptions, filters []*status.Filter, onlyUnhealthy bool) (*status.ObjectTree, error) {
+	cluster, err := fetchCluster(ctx, c, key)
+	if err != nil {
+		return nil, err
+	}
+
+	objs, err := status.Discovery(ctx, c, cluster, options)
+	if err != nil {
+		return nil, err
+	}
+
+	pruneTree(objs, filters, onlyUnhealthy)
+	return objs, nil
+}
+
+// fetchCluster returns the Cluster with the given key.
+func fetchCluster(ctx context.Context, c client.Client, key client.ObjectKey) (*clusterv1.Cluster, error) {
+	cluster := &clusterv1.Cluster{}
+	if err := c.Get(ctx, key, cluster); err != nil {
+		return nil, err
+	}
+	setClusterKind(cluster)
+	return cluster, nil
+}
+
+// listClusters returns the Clusters in the given namespace, or in all the namespaces if namespace is empty.
+func listClusters(ctx context.Context, c client.Client, namespace string) ([]*clusterv1.Cluster, error) {
+	clusterList := &clusterv1.ClusterList{}
+	if err := c.List(ctx, clusterList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	clusters := make([]*clusterv1.Cluster, 0, len(clusterList.Items))
+	for i := range clusterList.Items {
+		cluster := &clusterList.Items[i]
+		setClusterKind(cluster)
+		clusters = append(clusters, cluster)
+	}
+	return clusters, nil
+}
+
+// setClusterKind sets the Kind of a Cluster read with the typed client.
+func setClusterKind(cluster *clusterv1.Cluster) {
+	cluster.Kind = "Cluster" // TODO: investigate why this is empty
+}
+
+// parseFilters parses a list of filter expressions.
+func parseFilters(expressions []string) ([]*status.Filter, error) {
+	var filters []*status.Filter
+	for _, f := range expressions {
+		filter, err := status.ParseFilter(f)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+	return filters, nil
+}
+
+// pruneTree keeps only the objects matching at least one of the filters, if any, and only the unhealthy objects,
+// if onlyUnhealthy is set, together with their ancestors.
+func pruneTree(objs *status.ObjectTree, filters []*status.Filter, onlyUnhealthy bool) {
+	if len(filters) > 0 {
+		objs.Filter(func(node *status.Node) bool {
+			for _, f := range filters {
+				if f.Match(node) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+
+	if onlyUnhealthy {
+		objs.Filter(func(node *status.Node) bool {
+			return !node.IsHealthy()
+		})
+	}
+}
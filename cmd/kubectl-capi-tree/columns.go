@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+)
+
+// column defines an optional column for the tree view.
+type column struct {
+	header string
+	value  func(node *status.Node) string
+}
+
+var optionalColumns = map[string]column{
+	"version": {
+		header: "VERSION",
+		value:  getVersion,
+	},
+	"phase": {
+		header: "PHASE",
+		value: func(node *status.Node) string {
+			return status.GetPhase(node.Object)
+		},
+	},
+	"replicas": {
+		header: "REPLICAS",
+		value:  getReplicas,
+	},
+	"node": {
+		header: "NODE",
+		value: func(node *status.Node) string {
+			return status.GetNodeRef(node.Object)
+		},
+	},
+	"generation": {
+		header: "OBSERVED/GENERATION",
+		value:  getGeneration,
+	},
+	"provider-id": {
+		header: "PROVIDER ID",
+		value: func(node *status.Node) string {
+			return status.GetProviderID(node.Object)
+		},
+	},
+}
+
+// parseColumns parses a list of comma separated optional columns.
+func parseColumns(s string) ([]string, error) {
+	var columns []string
+	for _, c := range strings.Split(s, ",") {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if c == "" {
+			continue
+		}
+		if _, ok := optionalColumns[c]; !ok {
+			return nil, fmt.Errorf("invalid column %q, valid columns are %s", c, strings.Join(validColumns(), ", "))
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+func validColumns() []string {
+	var columns []string
+	for c := range optionalColumns {
+		columns = append(columns, c)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func getColumnHeaders(options treeViewOptions) []string {
+	var headers []string
+	for _, c := range options.Columns {
+		headers = append(headers, optionalColumns[c].header)
+	}
+	return headers
+}
+
+func getColumnValues(node *status.Node, options treeViewOptions) []string {
+	var values []string
+	for _, c := range options.Columns {
+		values = append(values, optionalColumns[c].value(node))
+	}
+	return values
+}
+
+// getVersion returns the Kubernetes version of an object, and for control planes and MachineDeployments
+// also the number and the percentage of machines already at the desired version.
+func getVersion(node *status.Node) string {
+	rollout := node.VersionRollout
+	if rollout == nil {
+		return node.Version
+	}
+
+	rolloutColor := green
+	if rollout.UpToDate < rollout.Total {
+		rolloutColor = yellow
+	}
+	percentage := 100
+	if rollout.Total > 0 {
+		percentage = rollout.UpToDate * 100 / rollout.Total
+	}
+	return fmt.Sprintf("%s %s", rollout.Version, rolloutColor.Sprintf("%d/%d (%d%%)", rollout.UpToDate, rollout.Total, percentage))
+}
+
+// getReplicas returns the ready and the desired replicas for an object.
+func getReplicas(node *status.Node) string {
+	replicas := status.GetReplicas(node.Object)
+	if replicas == nil {
+		return ""
+	}
+
+	replicasColor := green
+	if replicas.Ready < replicas.Desired {
+		replicasColor = yellow
+	}
+	return replicasColor.Sprintf("%d/%d", replicas.Ready, replicas.Desired)
+}
+
+// getGeneration returns the generation observed by the controller and the generation of an object.
+func getGeneration(node *status.Node) string {
+	observedGeneration, ok := status.GetObservedGeneration(node.Object)
+	if !ok {
+		return ""
+	}
+
+	generationColor := green
+	if node.Stale {
+		generationColor = yellow
+	}
+	return generationColor.Sprintf("%d/%d", observedGeneration, node.Object.GetGeneration())
+}
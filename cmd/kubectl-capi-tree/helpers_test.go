@@ -0,0 +1,99 @@
+package main
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// testCluster returns a ready Cluster in the ns namespace with the given name, also used as UID.
+func testCluster(name string) *clusterv1.Cluster {
+	cluster := &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Cluster",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			UID:       types.UID(name),
+		},
+	}
+	conditions.MarkTrue(cluster, clusterv1.ReadyCondition)
+	return cluster
+}
+
+// testMachineDeployment returns a MachineDeployment in the given cluster with the given name, also used as UID.
+func testMachineDeployment(cluster *clusterv1.Cluster, name string) *clusterv1.MachineDeployment {
+	return &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MachineDeployment",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      name,
+			UID:       types.UID(name),
+			Labels:    map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+		},
+	}
+}
+
+// testMachineSet returns a MachineSet in the given cluster controlled by the given MachineDeployment.
+func testMachineSet(cluster *clusterv1.Cluster, name string, md *clusterv1.MachineDeployment) *clusterv1.MachineSet {
+	return &clusterv1.MachineSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MachineSet",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       cluster.Namespace,
+			Name:            name,
+			UID:             types.UID(name),
+			Labels:          map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(md, clusterv1.GroupVersion.WithKind("MachineDeployment"))},
+		},
+	}
+}
+
+// testMachine returns a Machine in the given cluster controlled by the given MachineSet, with the given ready
+// condition, if any.
+func testMachine(cluster *clusterv1.Cluster, name string, ms *clusterv1.MachineSet, ready *clusterv1.Condition) *clusterv1.Machine {
+	m := &clusterv1.Machine{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Machine",
+			APIVersion: clusterv1.GroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       cluster.Namespace,
+			Name:            name,
+			UID:             types.UID(name),
+			Labels:          map[string]string{clusterv1.ClusterLabelName: cluster.Name},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(ms, clusterv1.GroupVersion.WithKind("MachineSet"))},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName: cluster.Name,
+		},
+	}
+	if ready != nil {
+		conditions.Set(m, ready)
+	}
+	return m
+}
+
+// testClusterObjects returns the objects for a cluster with a MachineDeployment with two ready machines and a machine
+// not ready.
+func testClusterObjects(cluster *clusterv1.Cluster) []runtime.Object {
+	md := testMachineDeployment(cluster, cluster.Name+"-md")
+	ms := testMachineSet(cluster, cluster.Name+"-ms", md)
+	return []runtime.Object{
+		cluster,
+		md,
+		ms,
+		testMachine(cluster, cluster.Name+"-m1", ms, conditions.TrueCondition(clusterv1.ReadyCondition)),
+		testMachine(cluster, cluster.Name+"-m2", ms, conditions.TrueCondition(clusterv1.ReadyCondition)),
+		testMachine(cluster, cluster.Name+"-m3", ms, conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, "")),
+	}
+}
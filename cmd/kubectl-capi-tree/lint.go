@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintDisableRules string
+)
+
+// lintCmd represents the lint command.
+var lintCmd = &cobra.Command{
+	Use:          "lint CLUSTER",
+	Short:        "Check the conditions of all the objects in a cluster against the Cluster API condition conventions",
+	SilenceUsage: true, // for when RunE returns an error
+	Args:         cobra.ExactArgs(1),
+	RunE:         runLint,
+}
+
+func runLint(command *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	var disabledRules []string
+	for _, r := range strings.Split(lintDisableRules, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if !containsString(status.LintRules, r) {
+			return fmt.Errorf("invalid lint rule %q, valid rules are %s", r, strings.Join(status.LintRules, ", "))
+		}
+		disabledRules = append(disabledRules, r)
+	}
+
+	c, cluster, err := getCluster(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Discovery the cluster status
+	// NB. all the objects must be linted, so hiding and grouping objects is disabled.
+	objs, err := status.Discovery(ctx, c, cluster, status.DiscoverOptions{
+		DisableNoEcho:       true,
+		DisableGroupObjects: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	var violations []status.LintViolation
+	for _, v := range objs.Lint() {
+		if containsString(disabledRules, v.Rule) {
+			continue
+		}
+		violations = append(violations, v)
+	}
+
+	// Output the violations on the CLI
+	if len(violations) == 0 {
+		fmt.Fprintln(os.Stderr, green.Sprint("No violations found"))
+		return nil
+	}
+	lintView(color.Output, violations)
+	return fmt.Errorf("found %d violations", len(violations))
+}
+
+// lintView prints the lint violations to out stream.
+func lintView(out io.Writer, violations []status.LintViolation) {
+	tbl := uitable.New()
+	tbl.Separator = "  "
+	tbl.AddRow("OBJECT", "CONDITION", "RULE", "MESSAGE")
+	for _, v := range violations {
+		tbl.AddRow(
+			getObjectName(v.Node),
+			cyan.Sprint(v.Condition),
+			yellow.Sprint(v.Rule),
+			truncateMessage(v.Message))
+	}
+	fmt.Fprintln(out, tbl)
+}
+
+func init() {
+	lintCmd.Flags().StringVar(&lintDisableRules, "disable-rules", "", fmt.Sprintf("list of comma separated rules to disable (%s)", strings.Join(status.LintRules, ", ")))
+}
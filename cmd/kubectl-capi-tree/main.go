@@ -2,10 +2,15 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"regexp"
+	"strings"
 
-	"github.com/fabriziopandini/capi-conditions/cmd/kubectl-capi-tree/status"
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
@@ -23,6 +28,19 @@ var (
 	showOtherConditions string
 	disableNoEcho       bool
 	disableGroupObjects bool
+	groupBy             string
+	groupingPattern     string
+	groupingMinSize     int
+	groupingParents     string
+	byFailureDomain     bool
+	showEvents          bool
+	showDeletion        bool
+	showUpgrade         bool
+	explain             bool
+	stuckThresholds     string
+	columns             string
+	filters             []string
+	onlyUnhealthy       bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -37,44 +55,76 @@ var rootCmd = &cobra.Command{
 func run(command *cobra.Command, args []string) error {
 	ctx := context.Background()
 
-	name := args[0]
-	namespace := getNamespace()
+	treeColumns, err := parseColumns(columns)
+	if err != nil {
+		return err
+	}
+	if showUpgrade && !containsString(treeColumns, "version") {
+		treeColumns = append([]string{"version"}, treeColumns...)
+	}
 
-	restConfig, err := cf.ToRESTConfig()
+	treeGroupBy, err := status.ParseGroupBy(groupBy)
 	if err != nil {
 		return err
 	}
-	restConfig.QPS = 1000
-	restConfig.Burst = 1000
+	if showUpgrade && !containsString(treeGroupBy, status.GroupByVersion) {
+		treeGroupBy = append(treeGroupBy, status.GroupByVersion)
+	}
+
+	var treeGroupingPattern *regexp.Regexp
+	if groupingPattern != "" {
+		treeGroupingPattern, err = regexp.Compile(groupingPattern)
+		if err != nil {
+			return err
+		}
+	}
 
-	c, err := client.New(restConfig, client.Options{Scheme: Scheme})
+	var treeGroupingParents []string
+	for _, p := range strings.Split(groupingParents, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			treeGroupingParents = append(treeGroupingParents, p)
+		}
+	}
+
+	treeStuckThresholds, err := status.ParseStuckThresholds(stuckThresholds)
 	if err != nil {
 		return err
 	}
 
-	// Fetch the Cluster instance.
-	cluster := &clusterv1.Cluster{}
-	clusterKey := client.ObjectKey{
-		Namespace: namespace,
-		Name:      name,
+	treeFilters, err := parseFilters(filters)
+	if err != nil {
+		return err
 	}
-	if err := c.Get(ctx, clusterKey, cluster); err != nil {
+
+	c, err := getClient()
+	if err != nil {
 		return err
 	}
-	cluster.Kind = "Cluster" // TODO: investigate why this is empty
 
-	// Discovery the cluster status
-	objs, err := status.Discovery(ctx, c, cluster, status.DiscoverOptions{
-		ShowOtherConditions: showOtherConditions,
-		DisableNoEcho:       disableNoEcho,
-		DisableGroupObjects: disableGroupObjects,
-	})
+	// Discovery the cluster status, keeping only the objects matching at least one of the filters and, if requested,
+	// only the unhealthy objects, and their ancestors
+	objs, err := discoverCluster(ctx, c, client.ObjectKey{Namespace: getNamespace(), Name: args[0]}, status.DiscoverOptions{
+		ShowOtherConditions:     showOtherConditions,
+		DisableNoEcho:           disableNoEcho || showDeletion,
+		DisableGroupObjects:     disableGroupObjects,
+		GroupBy:                 treeGroupBy,
+		GroupingMessagePattern:  treeGroupingPattern,
+		GroupingMinSize:         groupingMinSize,
+		GroupingParents:         treeGroupingParents,
+		OrganizeByFailureDomain: byFailureDomain,
+		ShowEvents:              showEvents,
+		StuckThresholds:         treeStuckThresholds,
+	}, treeFilters, onlyUnhealthy)
 	if err != nil {
 		return err
 	}
 
 	// Output the status on the CLI
-	treeView(os.Stderr, objs, cluster)
+	treeView(color.Output, objs, treeViewOptions{
+		ShowDeletion: showDeletion,
+		Columns:      treeColumns,
+		Explain:      explain,
+	})
 
 	return nil
 }
@@ -89,6 +139,42 @@ func versionString() string {
 	return "v" + version
 }
 
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// getClient returns a client for the management cluster.
+func getClient() (client.Client, error) {
+	restConfig, err := cf.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	restConfig.QPS = 1000
+	restConfig.Burst = 1000
+
+	return client.New(restConfig, client.Options{Scheme: Scheme})
+}
+
+// getCluster returns a client for the management cluster and the Cluster with the given name.
+func getCluster(ctx context.Context, name string) (client.Client, *clusterv1.Cluster, error) {
+	c, err := getClient()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Fetch the Cluster instance.
+	cluster, err := fetchCluster(ctx, c, client.ObjectKey{Namespace: getNamespace(), Name: name})
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, cluster, nil
+}
+
 func getNamespace() string {
 	if v := *cf.Namespace; v != "" {
 		return v
@@ -103,13 +189,32 @@ func getNamespace() string {
 
 func init() {
 	_ = clusterv1.AddToScheme(Scheme)
+	_ = corev1.AddToScheme(Scheme)
 
 	cf = genericclioptions.NewConfigFlags(true)
-	cf.AddFlags(rootCmd.Flags())
+	cf.AddFlags(rootCmd.PersistentFlags())
 
 	rootCmd.Flags().StringVar(&showOtherConditions, "show-all-conditions", "", " list of comma separated kind or kind/name for which we should show all the object's conditions (all to show conditions for all the objects)")
 	rootCmd.Flags().BoolVar(&disableNoEcho, "disable-no-echo", false, "Disable hiding of a MachineInfrastructure and BootstrapConfig when ready condition is true or it has the Status, Severity and Reason of the machine's object")
 	rootCmd.Flags().BoolVar(&disableGroupObjects, "disable-grouping", false, "Disable grouping machines when ready condition has the same Status, Severity and Reason")
+	rootCmd.Flags().StringVar(&groupBy, "group-by", strings.Join(status.DefaultGroupBy, ","), fmt.Sprintf("list of comma separated keys that machines must have in common in order to be grouped (%s)", strings.Join(status.GroupByKeys, ", ")))
+	rootCmd.Flags().StringVar(&groupingPattern, "grouping-message-pattern", "", "Regular expression applied to the ready condition's message when grouping by message; machines are grouped if the part of the message matching the pattern (or its first sub-match) is the same")
+	rootCmd.Flags().IntVar(&groupingMinSize, "grouping-min-size", 2, "Minimum number of machines for creating a group")
+	rootCmd.Flags().StringVar(&groupingParents, "grouping-parents", "", "list of comma separated kinds for which the children should be grouped (default to the control plane and MachineDeployments)")
+	rootCmd.Flags().BoolVar(&byFailureDomain, "by-failure-domain", false, "Organize machines under the control plane and MachineDeployments by failure domain, showing the number of ready machines for each failure domain")
+	rootCmd.Flags().BoolVar(&showEvents, "events", false, "Show the most recent warning events for each object")
+	rootCmd.Flags().BoolVar(&showDeletion, "deletion", false, "Show for objects being deleted how long the deletion is going on, the remaining finalizers and the objects blocking the deletion; implies --disable-no-echo")
+	rootCmd.Flags().BoolVar(&explain, "explain", false, "Show explanations and hints for well-known condition reasons")
+	rootCmd.Flags().StringVar(&stuckThresholds, "stuck-threshold", "", "list of comma separated thresholds for considering a False or Unknown condition as stuck, overriding the defaults, e.g. default=45m,reason:WaitingForInfrastructure=20m,kind:Machine=30m")
+	rootCmd.Flags().BoolVar(&showUpgrade, "upgrade", false, "Show the Kubernetes version of machines and the progress of version rollouts, grouping machines by version")
+	rootCmd.Flags().StringArrayVar(&filters, "filter", nil, "Show only the objects matching the filter, and their ancestors, e.g. kind=Machine,severity>=Warning or age>30m; supported keys are kind, name, status, severity, reason, message and age. If repeated, objects matching any of the filters are shown")
+	rootCmd.Flags().BoolVar(&onlyUnhealthy, "only-unhealthy", false, "Show only the objects with a ready condition not true or reporting a failure, and their ancestors")
+	rootCmd.Flags().StringVar(&columns, "columns", "", fmt.Sprintf("list of comma separated optional columns to show (%s)", strings.Join(validColumns(), ", ")))
+
+	rootCmd.AddCommand(timelineCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(moveCheckCmd)
 }
 
 func main() {
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"time"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	objectLabels    = []string{"namespace", "cluster", "kind", "name"}
+	conditionLabels = append(objectLabels, "condition")
+
+	conditionStatusDesc = prometheus.NewDesc(
+		"capi_condition_status",
+		"The status of a condition, 1 for the current status and 0 for the others.",
+		append(conditionLabels, "status"), nil)
+	conditionSeverityDesc = prometheus.NewDesc(
+		"capi_condition_severity",
+		"The severity of a condition: 0 for None, 1 for Info, 2 for Warning and 3 for Error.",
+		conditionLabels, nil)
+	conditionTimeInStateDesc = prometheus.NewDesc(
+		"capi_condition_time_in_state_seconds",
+		"The time elapsed since the last transition of a condition.",
+		conditionLabels, nil)
+	conditionStuckDesc = prometheus.NewDesc(
+		"capi_condition_stuck",
+		"1 if a condition is False or Unknown for longer than the stuck threshold, 0 otherwise.",
+		conditionLabels, nil)
+	groupSizeDesc = prometheus.NewDesc(
+		"capi_group_size",
+		"The number of machines under a parent object, e.g. a MachineDeployment, with the same status, severity and reason of the ready condition.",
+		[]string{"namespace", "cluster", "kind", "parent_kind", "parent_name", "status", "severity", "reason"}, nil)
+	discoveryFailuresDesc = prometheus.NewDesc(
+		"capi_discovery_failures_total",
+		"The number of failures when discovering the status of the clusters.",
+		nil, nil)
+	lastRefreshDesc = prometheus.NewDesc(
+		"capi_discovery_last_refresh_timestamp_seconds",
+		"The time of the last refresh of the status of the clusters.",
+		nil, nil)
+)
+
+// fleetCollector collects metrics about the status of all the clusters in a fleet.
+// NB. The fleet is discovered with grouping disabled, so all the objects report their conditions; the group size
+// metric reports how machines would be grouped, with a number of series proportional to the number of distinct states.
+type fleetCollector struct {
+	fleet *fleet
+}
+
+func newFleetCollector(f *fleet) *fleetCollector {
+	return &fleetCollector{fleet: f}
+}
+
+// Describe implements prometheus.Collector.
+func (c *fleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- conditionStatusDesc
+	ch <- conditionSeverityDesc
+	ch <- conditionTimeInStateDesc
+	ch <- conditionStuckDesc
+	ch <- groupSizeDesc
+	ch <- discoveryFailuresDesc
+	ch <- lastRefreshDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *fleetCollector) Collect(ch chan<- prometheus.Metric) {
+	now := time.Now()
+	c.fleet.forEachCluster(func(key client.ObjectKey, objs *status.ObjectTree) {
+		groupSizes := map[[8]string]int{}
+		objs.Walk(func(node *status.Node, _ int) bool {
+			if node.Kind == "Machine" && !node.Virtual {
+				var groupKey [8]string
+				copy(groupKey[:], groupSizeLabels(key, objs, node))
+				groupSizes[groupKey]++
+			}
+			for i := range node.Conditions {
+				collectCondition(ch, now, append([]string{key.Namespace, key.Name, node.Kind, node.Name}, string(node.Conditions[i].Type)), node, &node.Conditions[i])
+			}
+			return true
+		})
+
+		for groupKey, size := range groupSizes {
+			ch <- prometheus.MustNewConstMetric(groupSizeDesc, prometheus.GaugeValue, float64(size), groupKey[:]...)
+		}
+	})
+
+	c.fleet.lock.RLock()
+	defer c.fleet.lock.RUnlock()
+	ch <- prometheus.MustNewConstMetric(discoveryFailuresDesc, prometheus.CounterValue, float64(c.fleet.discoveryFails))
+	if !c.fleet.lastRefresh.IsZero() {
+		ch <- prometheus.MustNewConstMetric(lastRefreshDesc, prometheus.GaugeValue, float64(c.fleet.lastRefresh.Unix()))
+	}
+}
+
+func collectCondition(ch chan<- prometheus.Metric, now time.Time, labels []string, node *status.Node, condition *clusterv1.Condition) {
+	for _, s := range []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown} {
+		value := 0.0
+		if condition.Status == s {
+			value = 1
+		}
+		statusLabels := append(append([]string{}, labels...), string(s))
+		ch <- prometheus.MustNewConstMetric(conditionStatusDesc, prometheus.GaugeValue, value, statusLabels...)
+	}
+
+	ch <- prometheus.MustNewConstMetric(conditionSeverityDesc, prometheus.GaugeValue, severityValue(condition.Severity), labels...)
+
+	if !condition.LastTransitionTime.IsZero() {
+		ch <- prometheus.MustNewConstMetric(conditionTimeInStateDesc, prometheus.GaugeValue, now.Sub(condition.LastTransitionTime.Time).Seconds(), labels...)
+	}
+
+	stuck := 0.0
+	if node.IsConditionStuck(condition.Type) {
+		stuck = 1
+	}
+	ch <- prometheus.MustNewConstMetric(conditionStuckDesc, prometheus.GaugeValue, stuck, labels...)
+}
+
+func groupSizeLabels(key client.ObjectKey, objs *status.ObjectTree, node *status.Node) []string {
+	var parentKind, parentName string
+	if parent := objs.GetParent(node.ID); parent != nil {
+		parentKind, parentName = parent.Kind, parent.Name
+	}
+	var readyStatus, severity, reason string
+	if ready := node.GetReadyCondition(); ready != nil {
+		readyStatus, severity, reason = string(ready.Status), string(ready.Severity), ready.Reason
+	}
+	return []string{key.Namespace, key.Name, node.Kind, parentKind, parentName, readyStatus, severity, reason}
+}
+
+func severityValue(severity clusterv1.ConditionSeverity) float64 {
+	switch severity {
+	case clusterv1.ConditionSeverityError:
+		return 3
+	case clusterv1.ConditionSeverityWarning:
+		return 2
+	case clusterv1.ConditionSeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_fleetCollectorCollect(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := testCluster("cluster")
+	md := testMachineDeployment(cluster, "md")
+	ms := testMachineSet(cluster, "ms", md)
+
+	objs := status.NewObjectTree(cluster, status.ObjectTreeOptions{})
+	mdNode := objs.Add(objs.GetRoot(), md, status.GroupingObject(true))
+	objs.Add(mdNode, testMachine(cluster, "m1", ms, conditions.TrueCondition(clusterv1.ReadyCondition)))
+	objs.Add(mdNode, testMachine(cluster, "m2", ms, conditions.TrueCondition(clusterv1.ReadyCondition)))
+	objs.Add(mdNode, testMachine(cluster, "m3", ms, conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, "")))
+
+	f := &fleet{
+		clusters: map[client.ObjectKey]*status.ObjectTree{
+			{Namespace: "ns", Name: "cluster"}: objs,
+		},
+		discoveryFails: 2,
+	}
+
+	expected := `
+# HELP capi_condition_severity The severity of a condition: 0 for None, 1 for Info, 2 for Warning and 3 for Error.
+# TYPE capi_condition_severity gauge
+capi_condition_severity{cluster="cluster",condition="Ready",kind="Cluster",name="cluster",namespace="ns"} 0
+capi_condition_severity{cluster="cluster",condition="Ready",kind="Machine",name="m1",namespace="ns"} 0
+capi_condition_severity{cluster="cluster",condition="Ready",kind="Machine",name="m2",namespace="ns"} 0
+capi_condition_severity{cluster="cluster",condition="Ready",kind="Machine",name="m3",namespace="ns"} 2
+# HELP capi_condition_status The status of a condition, 1 for the current status and 0 for the others.
+# TYPE capi_condition_status gauge
+capi_condition_status{cluster="cluster",condition="Ready",kind="Cluster",name="cluster",namespace="ns",status="False"} 0
+capi_condition_status{cluster="cluster",condition="Ready",kind="Cluster",name="cluster",namespace="ns",status="True"} 1
+capi_condition_status{cluster="cluster",condition="Ready",kind="Cluster",name="cluster",namespace="ns",status="Unknown"} 0
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m1",namespace="ns",status="False"} 0
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m1",namespace="ns",status="True"} 1
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m1",namespace="ns",status="Unknown"} 0
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m2",namespace="ns",status="False"} 0
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m2",namespace="ns",status="True"} 1
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m2",namespace="ns",status="Unknown"} 0
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m3",namespace="ns",status="False"} 1
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m3",namespace="ns",status="True"} 0
+capi_condition_status{cluster="cluster",condition="Ready",kind="Machine",name="m3",namespace="ns",status="Unknown"} 0
+# HELP capi_discovery_failures_total The number of failures when discovering the status of the clusters.
+# TYPE capi_discovery_failures_total counter
+capi_discovery_failures_total 2
+# HELP capi_group_size The number of machines under a parent object, e.g. a MachineDeployment, with the same status, severity and reason of the ready condition.
+# TYPE capi_group_size gauge
+capi_group_size{cluster="cluster",kind="Machine",namespace="ns",parent_kind="MachineDeployment",parent_name="md",reason="",severity="",status="True"} 2
+capi_group_size{cluster="cluster",kind="Machine",namespace="ns",parent_kind="MachineDeployment",parent_name="md",reason="Foo",severity="Warning",status="False"} 1
+`
+	g.Expect(testutil.CollectAndCompare(newFleetCollector(f), strings.NewReader(expected),
+		"capi_condition_severity", "capi_condition_status", "capi_discovery_failures_total", "capi_group_size")).To(Succeed())
+
+	// NB. The last refresh is reported only after the first refresh.
+	g.Expect(testutil.CollectAndCompare(newFleetCollector(f), strings.NewReader(""), "capi_discovery_last_refresh_timestamp_seconds")).To(Succeed())
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+)
+
+// moveCheckCmd represents the move-check command.
+var moveCheckCmd = &cobra.Command{
+	Use:          "move-check CLUSTER",
+	Short:        "Check a cluster is safe to be moved to another management cluster",
+	SilenceUsage: true, // for when RunE returns an error
+	Args:         cobra.ExactArgs(1),
+	RunE:         runMoveCheck,
+}
+
+func runMoveCheck(command *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	c, cluster, err := getCluster(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Discovery the cluster status
+	// NB. all the objects must be checked, so hiding and grouping objects is disabled.
+	objs, err := status.Discovery(ctx, c, cluster, status.DiscoverOptions{
+		DisableNoEcho:       true,
+		DisableGroupObjects: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	issues := objs.CheckMove()
+	leftBehind, err := status.CheckLeftBehind(ctx, c, objs)
+	if err != nil {
+		return err
+	}
+	issues = append(issues, leftBehind...)
+
+	// Output the issues on the CLI
+	if len(issues) == 0 {
+		fmt.Fprintln(os.Stderr, green.Sprintf("Cluster %s/%s is safe to move", cluster.Namespace, cluster.Name))
+		return nil
+	}
+	moveCheckView(color.Output, issues)
+	return fmt.Errorf("found %d issues preventing to move cluster %s/%s", len(issues), cluster.Namespace, cluster.Name)
+}
+
+// moveCheckView prints the move issues to out stream.
+func moveCheckView(out io.Writer, issues []status.MoveIssue) {
+	tbl := uitable.New()
+	tbl.Separator = "  "
+	tbl.AddRow("OBJECT", "REASON", "MESSAGE")
+	for _, i := range issues {
+		tbl.AddRow(
+			i.Object,
+			yellow.Sprint(i.Reason),
+			truncateMessage(i.Message))
+	}
+	fmt.Fprintln(out, tbl)
+}
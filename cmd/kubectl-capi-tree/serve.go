@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	serveMetrics         bool
+	serveListenAddress   string
+	serveRefreshInterval time.Duration
+	serveStuckThresholds string
+)
+
+// serveCmd represents the serve command.
+var serveCmd = &cobra.Command{
+	Use:          "serve",
+	Short:        "Serve the status of all the clusters over HTTP, as JSON and HTML, and optionally as Prometheus metrics",
+	SilenceUsage: true, // for when RunE returns an error
+	Args:         cobra.NoArgs,
+	RunE:         runServe,
+}
+
+func runServe(command *cobra.Command, args []string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	thresholds, err := status.ParseStuckThresholds(serveStuckThresholds)
+	if err != nil {
+		return err
+	}
+
+	c, err := getCachedClient()
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	api := &apiServer{
+		client:          c,
+		namespace:       *cf.Namespace,
+		stuckThresholds: thresholds,
+	}
+	api.register(mux)
+
+	if serveMetrics {
+		f := &fleet{
+			client:          c,
+			namespace:       *cf.Namespace,
+			stuckThresholds: thresholds,
+			clusters:        map[client.ObjectKey]*status.ObjectTree{},
+		}
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(newFleetCollector(f))
+		mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+		go f.run(ctx, serveRefreshInterval)
+	}
+
+	fmt.Fprintf(os.Stderr, "Serving on %s\n", serveListenAddress)
+	return http.ListenAndServe(serveListenAddress, mux)
+}
+
+// getCachedClient returns a client for the management cluster reading objects from a shared informer cache,
+// so the status of the clusters can be computed on every request without listing objects from the API server.
+// NB. Informers for the infrastructure, bootstrap and control plane kinds are started when the objects are read the first time.
+func getCachedClient() (client.Client, error) {
+	restConfig, err := cf.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := client.New(restConfig, client.Options{Scheme: Scheme})
+	if err != nil {
+		return nil, err
+	}
+
+	informerCache, err := cache.New(restConfig, cache.Options{Scheme: Scheme, Namespace: *cf.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	stop := make(chan struct{})
+	go func() {
+		if err := informerCache.Start(stop); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to start the informer cache: %v\n", err)
+			os.Exit(1)
+		}
+	}()
+	if !informerCache.WaitForCacheSync(stop) {
+		return nil, fmt.Errorf("failed to sync the informer cache")
+	}
+
+	return &client.DelegatingClient{
+		Reader:       informerCache,
+		Writer:       c,
+		StatusClient: c,
+	}, nil
+}
+
+// fleet holds the status of all the clusters, periodically refreshed by running discovery.
+type fleet struct {
+	client          client.Client
+	namespace       string
+	stuckThresholds *status.StuckThresholds
+
+	lock           sync.RWMutex
+	clusters       map[client.ObjectKey]*status.ObjectTree
+	discoveryFails int
+	lastRefresh    time.Time
+}
+
+// run refreshes the status of the clusters at the given interval, until the context is done.
+func (f *fleet) run(ctx context.Context, interval time.Duration) {
+	f.refresh(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			f.refresh(ctx)
+		}
+	}
+}
+
+// refresh runs discovery for all the clusters, replacing the status of the fleet.
+// NB. Clusters for which discovery fails are not included in the fleet until the next successful refresh.
+func (f *fleet) refresh(ctx context.Context) {
+	clusterList, err := listClusters(ctx, f.client, f.namespace)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list clusters: %v\n", err)
+		f.lock.Lock()
+		f.discoveryFails++
+		f.lock.Unlock()
+		return
+	}
+
+	fails := 0
+	clusters := map[client.ObjectKey]*status.ObjectTree{}
+	for _, cluster := range clusterList {
+		// NB. all the objects are required for metrics, so hiding and grouping objects is disabled; otherwise
+		// objects merged into a group would not report their conditions.
+		objs, err := status.Discovery(ctx, f.client, cluster, status.DiscoverOptions{
+			DisableNoEcho:       true,
+			DisableGroupObjects: true,
+			StuckThresholds:     f.stuckThresholds,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to discover the status of cluster %s/%s: %v\n", cluster.Namespace, cluster.Name, err)
+			fails++
+			continue
+		}
+		clusters[client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name}] = objs
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.clusters = clusters
+	f.discoveryFails += fails
+	f.lastRefresh = time.Now()
+}
+
+// forEachCluster calls fn for all the clusters in the fleet, sorted by namespace and name.
+func (f *fleet) forEachCluster(fn func(key client.ObjectKey, objs *status.ObjectTree)) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	keys := make([]client.ObjectKey, 0, len(f.clusters))
+	for k := range f.clusters {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Name < keys[j].Name
+	})
+	for _, k := range keys {
+		fn(k, f.clusters[k])
+	}
+}
+
+func init() {
+	serveCmd.Flags().BoolVar(&serveMetrics, "metrics", false, "Serve Prometheus metrics for the conditions of all the objects in all the clusters on /metrics, refreshed at the refresh interval")
+	serveCmd.Flags().StringVar(&serveListenAddress, "listen-address", ":8080", "The address to listen on for HTTP requests")
+	serveCmd.Flags().DurationVar(&serveRefreshInterval, "refresh-interval", time.Minute, "How often the status of the clusters should be refreshed for metrics")
+	serveCmd.Flags().StringVar(&serveStuckThresholds, "stuck-threshold", "", "list of comma separated thresholds for considering a False or Unknown condition as stuck, overriding the defaults, e.g. default=45m,reason:WaitingForInfrastructure=20m,kind:Machine=30m")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// failingListClient is a client failing to list objects of the given type.
+type failingListClient struct {
+	client.Client
+	failOn runtime.Object
+}
+
+func (c *failingListClient) List(ctx context.Context, list runtime.Object, opts ...client.ListOption) error {
+	if reflect.TypeOf(list) == reflect.TypeOf(c.failOn) {
+		return fmt.Errorf("failed to list %T", list)
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+func Test_fleetRefresh(t *testing.T) {
+	var objects []runtime.Object
+	objects = append(objects, testClusterObjects(testCluster("cluster1"))...)
+	objects = append(objects, testClusterObjects(testCluster("cluster2"))...)
+
+	tests := []struct {
+		name         string
+		failOn       runtime.Object
+		wantClusters []string
+		wantFails    int
+		wantRefresh  bool
+	}{
+		{
+			name:         "All the clusters are discovered",
+			wantClusters: []string{"cluster1", "cluster2"},
+			wantFails:    0,
+			wantRefresh:  true,
+		},
+		{
+			name:         "Failures listing clusters are counted and the fleet is not refreshed",
+			failOn:       &clusterv1.ClusterList{},
+			wantClusters: []string{"previous"},
+			wantFails:    1,
+			wantRefresh:  false,
+		},
+		{
+			name:         "Failures discovering clusters are counted for each cluster and the clusters are not included",
+			failOn:       &clusterv1.MachineList{},
+			wantClusters: nil,
+			wantFails:    2,
+			wantRefresh:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var c client.Client = fake.NewFakeClientWithScheme(Scheme, objects...)
+			if tt.failOn != nil {
+				c = &failingListClient{Client: c, failOn: tt.failOn}
+			}
+			f := &fleet{
+				client:    c,
+				namespace: "ns",
+				clusters: map[client.ObjectKey]*status.ObjectTree{
+					{Namespace: "ns", Name: "previous"}: status.NewObjectTree(testCluster("previous"), status.ObjectTreeOptions{}),
+				},
+			}
+
+			f.refresh(context.Background())
+
+			var clusters []string
+			f.forEachCluster(func(key client.ObjectKey, _ *status.ObjectTree) {
+				clusters = append(clusters, key.Name)
+			})
+			g.Expect(clusters).To(Equal(tt.wantClusters))
+			g.Expect(f.discoveryFails).To(Equal(tt.wantFails))
+			g.Expect(f.lastRefresh.IsZero()).To(Equal(!tt.wantRefresh))
+		})
+	}
+}
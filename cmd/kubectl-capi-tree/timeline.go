@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
+	"github.com/fatih/color"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+const (
+	// timelineBarWidth is the width of the bar showing the position of a step in the timeline.
+	timelineBarWidth = 30
+)
+
+var (
+	timelineReadyOnly bool
+)
+
+// timelineCmd represents the timeline command.
+var timelineCmd = &cobra.Command{
+	Use:          "timeline CLUSTER",
+	Short:        "Show the provisioning timeline of a cluster, computed from the object creation times and the condition transitions",
+	SilenceUsage: true, // for when RunE returns an error
+	Args:         cobra.ExactArgs(1),
+	RunE:         runTimeline,
+}
+
+func runTimeline(command *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	c, cluster, err := getCluster(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Discovery the cluster status
+	// NB. all the objects are required in the timeline, so hiding and grouping objects is disabled.
+	objs, err := status.Discovery(ctx, c, cluster, status.DiscoverOptions{
+		DisableNoEcho:       true,
+		DisableGroupObjects: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Output the timeline on the CLI
+	timelineView(color.Output, objs.Timeline())
+	return nil
+}
+
+// timelineView prints the timeline to out stream, with the time elapsed since the first step and since the previous step,
+// and a bar showing the position of each step in the timeline.
+func timelineView(out io.Writer, entries []status.TimelineEntry) {
+	tbl := uitable.New()
+	tbl.Separator = "  "
+	tbl.AddRow("ELAPSED", "DELTA", "", "OBJECT", "STEP", "STATUS", "REASON", "MESSAGE")
+
+	var start, end, previous time.Time
+	if len(entries) > 0 {
+		start = entries[0].Time.Time
+		end = entries[len(entries)-1].Time.Time
+		previous = start
+	}
+
+	for _, e := range entries {
+		if timelineReadyOnly && e.Step != string(clusterv1.ReadyCondition) && e.Step != status.TimelineCreated && e.Step != status.TimelineDeleted {
+			continue
+		}
+
+		stepColor := gray
+		if e.Status != "" {
+			stepColor = getCond(&clusterv1.Condition{Status: e.Status, Severity: e.Severity}).readyColor
+		}
+
+		tbl.AddRow(
+			duration.HumanDuration(e.Time.Sub(start)),
+			duration.HumanDuration(e.Time.Sub(previous)),
+			gray.Sprint(getTimelineBar(start, end, e.Time.Time)),
+			getObjectName(e.Node),
+			stepColor.Sprint(e.Step),
+			stepColor.Sprint(e.Status),
+			stepColor.Sprint(e.Reason),
+			truncateMessage(e.Message))
+		previous = e.Time.Time
+	}
+	fmt.Fprintln(out, tbl)
+}
+
+// getTimelineBar returns a bar with a marker in the position of t in the interval between start and end.
+func getTimelineBar(start, end, t time.Time) string {
+	pos := 0
+	if total := end.Sub(start); total > 0 {
+		pos = int(int64(timelineBarWidth-1) * int64(t.Sub(start)) / int64(total))
+	}
+	return fmt.Sprintf("%s%s%s", strings.Repeat("─", pos), "●", strings.Repeat(" ", timelineBarWidth-1-pos))
+}
+
+func init() {
+	timelineCmd.Flags().BoolVar(&timelineReadyOnly, "ready-only", false, "Show only object creation, deletion and ready condition transitions")
+}
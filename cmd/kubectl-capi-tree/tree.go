@@ -7,13 +7,12 @@ import (
 	"strings"
 	"time"
 
-	"github.com/fabriziopandini/capi-conditions/cmd/kubectl-capi-tree/status"
+	"github.com/fabriziopandini/capi-conditions/pkg/status"
 	"github.com/fatih/color"
 	"github.com/gosuri/uitable"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
@@ -21,6 +20,9 @@ const (
 	lastElemPrefix  = `└─`
 	indent          = "  "
 	pipe            = `│ `
+
+	// maxGroupMessages is the max number of distinct messages shown for a group node.
+	maxGroupMessages = 3
 )
 
 var (
@@ -30,15 +32,47 @@ var (
 	yellow = color.New(color.FgYellow)
 	white  = color.New(color.FgWhite)
 	cyan   = color.New(color.FgCyan)
+
+	boldRed    = color.New(color.FgRed, color.Bold)
+	boldYellow = color.New(color.FgYellow, color.Bold)
+	boldCyan   = color.New(color.FgCyan, color.Bold)
 )
 
+// treeViewOptions defines options for the presentation layer.
+type treeViewOptions struct {
+	// ShowDeletion adds details about finalizers and blocking children for objects being deleted.
+	ShowDeletion bool
+
+	// Columns is the list of optional columns to be added to the table, e.g. version or phase.
+	Columns []string
+
+	// Explain adds explanations and hints for well-known condition reasons.
+	Explain bool
+}
+
 // treeView prints object hierarchy to out stream.
-func treeView(out io.Writer, objs *status.ObjectTree, obj controllerutil.Object) {
+func treeView(out io.Writer, objs *status.ObjectTree, options treeViewOptions) {
 	tbl := uitable.New()
 	tbl.Separator = "  "
-	tbl.AddRow("NAME", "READY", "SEVERITY", "REASON", "SINCE", "MESSAGE")
-	treeViewInner("", tbl, objs, obj)
-	fmt.Fprintln(color.Output, tbl)
+	addRow(tbl, "NAME", getColumnHeaders(options), "READY", "SEVERITY", "REASON", "SINCE", "MESSAGE")
+	treeViewInner("", tbl, objs, objs.GetRoot(), options)
+
+	// If the cluster is paused, add a banner before the table, because conditions are not updated while paused.
+	if objs.IsPaused() {
+		root := objs.GetRoot()
+		fmt.Fprintln(out, boldCyan.Sprintf("!! PAUSED !! Reconciliation of Cluster %s/%s and of all its objects is paused, conditions might be out of date", root.Namespace, root.Name))
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintln(out, tbl)
+}
+
+// addRow adds a row to the table, with the values for the optional columns after the name.
+func addRow(tbl *uitable.Table, name string, optional []string, cells ...interface{}) {
+	row := []interface{}{name}
+	for _, o := range optional {
+		row = append(row, o)
+	}
+	tbl.AddRow(append(row, cells...)...)
 }
 
 // TODO: refactor
@@ -76,76 +110,90 @@ func getCond(c *clusterv1.Condition) cond {
 	v.status = string(c.Status)
 	v.severity = string(c.Severity)
 	v.reason = c.Reason
-	v.message = c.Message
-	if len(v.message) > 100 {
-		v.message = fmt.Sprintf("%s ...", v.message[:100])
-	}
+	v.message = truncateMessage(c.Message)
 	v.age = duration.HumanDuration(time.Since(c.LastTransitionTime.Time))
 
 	return v
 }
 
 // TODO: refactor ...
-func treeViewInner(prefix string, tbl *uitable.Table, objs *status.ObjectTree, obj controllerutil.Object) {
+func treeViewInner(prefix string, tbl *uitable.Table, objs *status.ObjectTree, node *status.Node, options treeViewOptions) {
 	v := cond{}
 	v.readyColor = gray
 
-	ready := status.GetReadyCondition(obj)
+	ready := node.GetReadyCondition()
 	if ready != nil {
 		v = getCond(ready)
 	}
 
-	name := getName(obj)
-	if status.IsGroupObject(obj) {
+	name := getName(node)
+	if node.IsGroup() {
 		name = white.Add(color.Bold).Sprintf(name)
-		items := strings.Split(status.GetGroupItems(obj), status.GroupItemsSeparator)
+		items := node.GroupItems
 		if len(items) <= 2 {
-			v.message = gray.Sprintf("See %s", strings.Join(items, status.GroupItemsSeparator))
+			v.message = gray.Sprintf("See %s", strings.Join(items, ", "))
 		} else {
-			v.message = gray.Sprintf("See %s, ...", strings.Join(items[:2], status.GroupItemsSeparator))
+			v.message = gray.Sprintf("See %s, ...", strings.Join(items[:2], ", "))
+		}
+		if stats := node.GroupStats; stats != nil && !stats.OldestTransition.Equal(&stats.NewestTransition) {
+			v.age = fmt.Sprintf("%s-%s",
+				duration.HumanDuration(time.Since(stats.NewestTransition.Time)),
+				duration.HumanDuration(time.Since(stats.OldestTransition.Time)))
 		}
 	}
-	if !obj.GetDeletionTimestamp().IsZero() {
+	if node.Stale {
+		name = fmt.Sprintf("%s %s", yellow.Sprintf("!! STALE !!"), name)
+	}
+	if node.Paused {
+		name = fmt.Sprintf("%s %s", boldCyan.Sprintf("!! PAUSED !!"), name)
+	}
+	if node.IsStuck() {
+		name = fmt.Sprintf("%s %s", boldYellow.Sprintf("!! STUCK !!"), name)
+	}
+	if node.IsDeleted() {
 		name = fmt.Sprintf("%s %s", red.Sprintf("!! DELETED !!"), name)
 	}
+	if node.IsFailed() {
+		name = fmt.Sprintf("%s %s", boldRed.Sprintf("!! FAILED !!"), name)
+	}
 
-	tbl.AddRow(
+	addRow(tbl,
 		fmt.Sprintf("%s%s", gray.Sprint(printPrefix(prefix)), name),
+		getColumnValues(node, options),
 		v.readyColor.Sprint(v.status),
 		v.readyColor.Sprint(v.severity),
 		v.readyColor.Sprint(v.reason),
 		v.age,
 		v.message)
 
-	chs := objs.GetObjectsByParent(obj.GetUID())
-
-	if status.IsShowConditionsObject(obj) {
-		otherConditions := status.GetOtherConditions(obj)
-		for i := range otherConditions {
-			cond := otherConditions[i]
-
-			p := ""
-			filler := strings.Repeat(" ", 10)
-			siblingsPipe := "  "
-			if len(chs) > 0 {
-				siblingsPipe = pipe
-			}
-			switch i {
-			case len(otherConditions) - 1:
-				p = prefix + siblingsPipe + filler + lastElemPrefix
-			default:
-				p = prefix + siblingsPipe + filler + firstElemPrefix
-			}
-
-			v = getCond(cond)
-			tbl.AddRow(
-				fmt.Sprintf("%s%s", gray.Sprint(printPrefix(p)), cyan.Sprint(cond.Type)),
-				v.readyColor.Sprint(v.status),
-				v.readyColor.Sprint(v.severity),
-				v.readyColor.Sprint(v.reason),
-				v.age,
-				v.message)
-		}
+	chs := objs.GetChildren(node.ID)
+
+	// Add rows for the object's failure, inconsistencies, explanation, group messages, conditions, events and deletion details, if any.
+	var details []detailRow
+	details = append(details, getFailureRows(node)...)
+	details = append(details, getInconsistencyRows(node)...)
+	if options.Explain {
+		details = append(details, getExplanationRows(ready, "")...)
+	}
+	details = append(details, getGroupMessageRows(node)...)
+	if node.ShowConditions {
+		details = append(details, getConditionRows(node, options)...)
+	}
+	details = append(details, getEventRows(node)...)
+	if options.ShowDeletion {
+		details = append(details, getDeletionRows(objs, node)...)
+	}
+
+	for i, d := range details {
+		p := getDetailPrefix(prefix, i, len(details), len(chs) > 0)
+		addRow(tbl,
+			fmt.Sprintf("%s%s", gray.Sprint(printPrefix(p)), d.name),
+			make([]string, len(options.Columns)),
+			d.status,
+			d.severity,
+			d.reason,
+			d.age,
+			d.message)
 	}
 
 	sort.Slice(chs, func(i, j int) bool {
@@ -155,36 +203,233 @@ func treeViewInner(prefix string, tbl *uitable.Table, objs *status.ObjectTree, o
 	for i, child := range chs {
 		switch i {
 		case len(chs) - 1:
-			treeViewInner(prefix+lastElemPrefix, tbl, objs, child)
+			treeViewInner(prefix+lastElemPrefix, tbl, objs, child, options)
 		default:
-			treeViewInner(prefix+firstElemPrefix, tbl, objs, child)
+			treeViewInner(prefix+firstElemPrefix, tbl, objs, child, options)
+		}
+	}
+}
+
+// detailRow is a row shown under an object, e.g. one of the object's conditions.
+type detailRow struct {
+	name     string
+	status   string
+	severity string
+	reason   string
+	age      string
+	message  string
+}
+
+func getConditionRows(node *status.Node, options treeViewOptions) []detailRow {
+	var rows []detailRow
+	for _, c := range node.GetOtherConditions() {
+		v := getCond(c)
+		name := cyan.Sprint(c.Type)
+		if node.IsConditionStuck(c.Type) {
+			name = fmt.Sprintf("%s %s", boldYellow.Sprintf("!! STUCK !!"), name)
+		}
+		rows = append(rows, detailRow{
+			name:     name,
+			status:   v.readyColor.Sprint(v.status),
+			severity: v.readyColor.Sprint(v.severity),
+			reason:   v.readyColor.Sprint(v.reason),
+			age:      v.age,
+			message:  v.message,
+		})
+		if options.Explain {
+			rows = append(rows, getExplanationRows(c, indent)...)
+		}
+	}
+	return rows
+}
+
+// getExplanationRows returns the rows with the explanation and the hint for a condition, if any;
+// the name prefix is used for distinguishing explanations of conditions from the explanation of the ready condition.
+func getExplanationRows(c *clusterv1.Condition, namePrefix string) []detailRow {
+	e := status.Explain(c)
+	if e == nil {
+		return nil
+	}
+	return []detailRow{
+		{
+			name:    gray.Sprintf("%sExplanation", namePrefix),
+			message: e.Description,
+		},
+		{
+			name:    gray.Sprintf("%sHint", namePrefix),
+			message: e.Hint,
+		},
+	}
+}
+
+func getFailureRows(node *status.Node) []detailRow {
+	failure := node.Failure
+	if failure == nil {
+		return nil
+	}
+	return []detailRow{
+		{
+			name:    red.Sprint("Failure"),
+			reason:  red.Sprint(failure.Reason),
+			message: red.Sprint(failure.Message),
+		},
+	}
+}
+
+func getInconsistencyRows(node *status.Node) []detailRow {
+	var rows []detailRow
+	for _, i := range node.Inconsistencies {
+		rows = append(rows, detailRow{
+			name:    yellow.Sprint("Inconsistency"),
+			message: yellow.Sprint(i),
+		})
+	}
+	return rows
+}
+
+func getGroupMessageRows(node *status.Node) []detailRow {
+	if node.GroupStats == nil {
+		return nil
+	}
+
+	messages := node.GroupStats.Messages
+	var rows []detailRow
+	for i, m := range messages {
+		if i == maxGroupMessages {
+			rows = append(rows, detailRow{
+				name:    gray.Sprint("Message"),
+				message: gray.Sprintf("... %d other messages", len(messages)-maxGroupMessages),
+			})
+			break
+		}
+		rows = append(rows, detailRow{
+			name:    gray.Sprintf("Message (x%d)", m.Count),
+			message: truncateMessage(m.Message),
+		})
+	}
+	return rows
+}
+
+// getEventRows returns the rows for the events of a node; events for other objects, e.g. the objects in a group
+// or the hidden children of the node, show the object they refer to.
+func getEventRows(node *status.Node) []detailRow {
+	var rows []detailRow
+	for _, e := range node.Events {
+		reason := e.Reason
+		if e.Count > 1 {
+			reason = fmt.Sprintf("%s (x%d)", e.Reason, e.Count)
+		}
+		name := "Event"
+		if e.InvolvedObject.UID != node.ID {
+			name = fmt.Sprintf("Event %s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Name)
+		}
+		rows = append(rows, detailRow{
+			name:     yellow.Sprint(name),
+			severity: yellow.Sprint(e.Type),
+			reason:   yellow.Sprint(reason),
+			age:      duration.HumanDuration(time.Since(status.GetEventTime(e))),
+			message:  truncateMessage(e.Message),
+		})
+	}
+	return rows
+}
+
+func getDeletionRows(objs *status.ObjectTree, node *status.Node) []detailRow {
+	deletion := objs.GetDeletion(node)
+	if deletion == nil {
+		return nil
+	}
+
+	var rows []detailRow
+	age := duration.HumanDuration(time.Since(deletion.Timestamp.Time))
+	if len(deletion.Finalizers) > 0 {
+		rows = append(rows, detailRow{
+			name:    red.Sprint("Finalizers"),
+			age:     age,
+			message: strings.Join(deletion.Finalizers, ", "),
+		})
+	}
+
+	if blocking := deletion.BlockingObjects; len(blocking) > 0 {
+		message := strings.Join(blocking, ", ")
+		if len(blocking) > 3 {
+			message = fmt.Sprintf("%s, ...", strings.Join(blocking[:3], ", "))
 		}
+		rows = append(rows, detailRow{
+			name:    red.Sprintf("Waiting for %d objects", len(blocking)),
+			age:     age,
+			message: message,
+		})
+	}
+	return rows
+}
+
+func truncateMessage(message string) string {
+	if len(message) > 100 {
+		return fmt.Sprintf("%s ...", message[:100])
+	}
+	return message
+}
+
+// getDetailPrefix returns the prefix for the i-th of the total rows shown under an object,
+// e.g. the object's conditions.
+func getDetailPrefix(prefix string, i, total int, hasChildren bool) string {
+	filler := strings.Repeat(" ", 10)
+	siblingsPipe := "  "
+	if hasChildren {
+		siblingsPipe = pipe
+	}
+	if i == total-1 {
+		return prefix + siblingsPipe + filler + lastElemPrefix
 	}
+	return prefix + siblingsPipe + filler + firstElemPrefix
 }
 
 // TODO: refactor, isTreeObject, objName, getTreePrefix
-func getName(obj controllerutil.Object) string {
-	if status.IsGroupObject(obj) {
-		items := strings.Split(status.GetGroupItems(obj), status.GroupItemsSeparator)
-		// TODO: derive "Machines" from the group
-		return fmt.Sprintf("%d Machines...", len(items))
+func getName(node *status.Node) string {
+	if node.IsGroup() {
+		return fmt.Sprintf("%d %ss...", len(node.GroupItems), node.Kind)
 	}
 
-	if status.IsVirtualObject(obj) {
-		return obj.GetName()
+	if node.Kind == status.FailureDomainKind {
+		return fmt.Sprintf("%s/%s %s", node.Kind, color.New(color.Bold).Sprint(node.Name), getFailureDomainStats(node))
+	}
+
+	if node.Virtual {
+		return node.Name
 	}
 
 	objName := fmt.Sprintf("%s/%s",
-		obj.GetObjectKind().GroupVersionKind().Kind,
-		color.New(color.Bold).Sprint(obj.GetName()))
+		node.Kind,
+		color.New(color.Bold).Sprint(node.Name))
 
 	name := objName
-	if objectPrefix := status.GetMetaName(obj); objectPrefix != "" {
+	if objectPrefix := node.MetaName; objectPrefix != "" {
 		name = fmt.Sprintf("%s - %s", objectPrefix, gray.Sprintf(name))
 	}
 	return name
 }
 
+// getFailureDomainStats returns the number of ready machines in a failure domain.
+func getFailureDomainStats(node *status.Node) string {
+	stats := node.FailureDomainStats
+	if stats == nil {
+		return ""
+	}
+
+	statsColor := green
+	if stats.Ready < stats.Total {
+		statsColor = yellow
+	}
+	return statsColor.Sprintf("(%d/%d ready)", stats.Ready, stats.Total)
+}
+
+// getObjectName returns the kind and the name of the object a node was created from, e.g. for showing
+// the object in flat lists.
+func getObjectName(node *status.Node) string {
+	return fmt.Sprintf("%s/%s", node.Kind, color.New(color.Bold).Sprint(node.Name))
+}
+
 func printPrefix(p string) string {
 	// this part is hacky af
 	if strings.HasSuffix(p, firstElemPrefix) {
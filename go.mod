@@ -7,6 +7,7 @@ require (
 	github.com/gosuri/uitable v0.0.4
 	github.com/mattn/go-runewidth v0.0.9 // indirect
 	github.com/onsi/gomega v1.10.1
+	github.com/prometheus/client_golang v1.5.1
 	github.com/spf13/cobra v1.0.0
 	k8s.io/api v0.17.8
 	k8s.io/apimachinery v0.17.8
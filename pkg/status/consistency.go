@@ -0,0 +1,93 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+// CheckConsistency sets Inconsistencies for all the nodes in the tree with a ready condition not matching
+// the ready condition of their children, including the children hidden because of the NoEcho option.
+// A node is inconsistent if the ready condition is true while some of the children are not ready, e.g. a Machine
+// reporting ready while its infrastructure or bootstrap object is not, or if the ready condition is not true
+// while all the children are ready and all the other conditions of the node are true, so nothing explains
+// why the node is not ready.
+// NB. Virtual nodes and group nodes are not checked, and they are not considered as children, because their
+// conditions are computed by the object tree; machines organized by failure domain are considered as children
+// of the control plane or of the MachineDeployment.
+// NB. CheckConsistency should be called before Group, because grouping removes the descendants of grouped nodes.
+func (od ObjectTree) CheckConsistency() {
+	od.Walk(func(node *Node, _ int) bool {
+		node.Inconsistencies = nil
+		if node.Virtual {
+			return true
+		}
+		ready := node.GetReadyCondition()
+		if ready == nil {
+			return true
+		}
+
+		var childrenReady, childrenNotReady []string
+		for _, child := range od.getConsistencyChildren(node) {
+			childReady := child.GetReadyCondition()
+			if childReady == nil {
+				continue
+			}
+			name := fmt.Sprintf("%s/%s", child.Kind, child.Name)
+			if childReady.Status == corev1.ConditionTrue {
+				childrenReady = append(childrenReady, name)
+				continue
+			}
+			childrenNotReady = append(childrenNotReady, name)
+		}
+		sort.Strings(childrenNotReady)
+
+		if ready.Status == corev1.ConditionTrue && len(childrenNotReady) > 0 {
+			node.Inconsistencies = append(node.Inconsistencies,
+				fmt.Sprintf("%s is True while %s not ready", clusterv1.ReadyCondition, describeNames(childrenNotReady)))
+		}
+		if ready.Status != corev1.ConditionTrue && len(childrenReady) > 0 && len(childrenNotReady) == 0 && allOtherConditionsTrue(node) {
+			node.Inconsistencies = append(node.Inconsistencies,
+				fmt.Sprintf("%s is %s while all the children and all the other conditions are ready", clusterv1.ReadyCondition, ready.Status))
+		}
+		return true
+	})
+}
+
+// getConsistencyChildren returns the children of a node to be considered when checking consistency, including the
+// hidden children and the machines organized by failure domain, but excluding virtual nodes.
+func (od ObjectTree) getConsistencyChildren(node *Node) []*Node {
+	var children []*Node
+	for _, child := range append(od.GetChildren(node.ID), node.HiddenChildren...) {
+		if child.Virtual {
+			if child.Kind == FailureDomainKind {
+				children = append(children, od.getConsistencyChildren(child)...)
+			}
+			continue
+		}
+		children = append(children, child)
+	}
+	return children
+}
+
+func allOtherConditionsTrue(node *Node) bool {
+	for _, c := range node.GetOtherConditions() {
+		if c.Status != corev1.ConditionTrue {
+			return false
+		}
+	}
+	return true
+}
+
+func describeNames(names []string) string {
+	if len(names) == 1 {
+		return fmt.Sprintf("%s is", names[0])
+	}
+	if len(names) > 3 {
+		return fmt.Sprintf("%s, ... (%d objects) are", strings.Join(names[:3], ", "), len(names))
+	}
+	return fmt.Sprintf("%s are", strings.Join(names, ", "))
+}
@@ -0,0 +1,77 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeCheckConsistency(t *testing.T) {
+	child := func(kind string, ready *clusterv1.Condition) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetKind(kind)
+		u.SetNamespace("ns")
+		u.SetName("m1")
+		u.SetUID(types.UID(kind))
+		conditions.UnstructuredSetter(u).SetConditions(clusterv1.Conditions{*ready})
+		return u
+	}
+
+	tests := []struct {
+		name              string
+		machineConditions []*clusterv1.Condition
+		infraReady        *clusterv1.Condition
+		wantInconsistent  bool
+	}{
+		{
+			name:              "Ready machine with ready children",
+			machineConditions: []*clusterv1.Condition{conditions.TrueCondition(clusterv1.ReadyCondition)},
+			infraReady:        conditions.TrueCondition(clusterv1.ReadyCondition),
+			wantInconsistent:  false,
+		},
+		{
+			name:              "Ready machine with infrastructure not ready",
+			machineConditions: []*clusterv1.Condition{conditions.TrueCondition(clusterv1.ReadyCondition)},
+			infraReady:        conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, ""),
+			wantInconsistent:  true,
+		},
+		{
+			name:              "Machine not ready with ready children",
+			machineConditions: []*clusterv1.Condition{conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, "")},
+			infraReady:        conditions.TrueCondition(clusterv1.ReadyCondition),
+			wantInconsistent:  true,
+		},
+		{
+			name: "Machine not ready with ready children, but another condition not true",
+			machineConditions: []*clusterv1.Condition{
+				conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, ""),
+				conditions.FalseCondition(clusterv1.MachineHealthCheckSuccededCondition, "Foo", clusterv1.ConditionSeverityWarning, ""),
+			},
+			infraReady:       conditions.TrueCondition(clusterv1.ReadyCondition),
+			wantInconsistent: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			machine := testMachine("m1", nil)
+			for _, c := range tt.machineConditions {
+				conditions.Set(machine, c)
+			}
+
+			objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+			machineNode := objs.Add(objs.GetRoot(), machine)
+			objs.Add(machineNode, child("InfrastructureMachine", tt.infraReady), NoEcho(true))
+			objs.Add(machineNode, child("BootstrapConfig", conditions.TrueCondition(clusterv1.ReadyCondition)), NoEcho(true))
+			objs.CheckConsistency()
+
+			g.Expect(machineNode.HiddenChildren).ToNot(BeEmpty())
+			g.Expect(machineNode.IsInconsistent()).To(Equal(tt.wantInconsistent))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Deletion describes the progress of the deletion of an object.
+type Deletion struct {
+	// Timestamp is the time the deletion started.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Finalizers are the finalizers still blocking the deletion of the object, if any.
+	Finalizers []string `json:"finalizers,omitempty"`
+
+	// BlockingObjects are the objects blocking the deletion of the object, in the Kind/name form and sorted by name;
+	// the objects included in group nodes are listed individually.
+	BlockingObjects []string `json:"blockingObjects,omitempty"`
+}
+
+// GetDeletion returns the progress of the deletion of the object a node was created from, or nil if the object
+// is not being deleted.
+func (od ObjectTree) GetDeletion(node *Node) *Deletion {
+	if !node.IsDeleted() {
+		return nil
+	}
+
+	deletion := &Deletion{
+		Timestamp:  *node.Object.GetDeletionTimestamp(),
+		Finalizers: node.Object.GetFinalizers(),
+	}
+	for _, b := range od.GetBlockingNodes(node.ID) {
+		if b.IsGroup() {
+			for _, item := range b.GroupItems {
+				deletion.BlockingObjects = append(deletion.BlockingObjects, fmt.Sprintf("%s/%s", b.Kind, item))
+			}
+			continue
+		}
+		deletion.BlockingObjects = append(deletion.BlockingObjects, fmt.Sprintf("%s/%s", b.Kind, b.Name))
+	}
+	sort.Strings(deletion.BlockingObjects)
+	return deletion
+}
@@ -0,0 +1,107 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeGetDeletion(t *testing.T) {
+	deletionTimestamp := metav1.NewTime(time.Now().Add(-5 * time.Minute).Truncate(time.Second))
+
+	infraMachine := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetKind("InfrastructureMachine")
+		u.SetNamespace("ns")
+		u.SetName(name)
+		u.SetUID(types.UID("infra-" + name))
+		conditions.UnstructuredSetter(u).SetConditions(clusterv1.Conditions{*conditions.TrueCondition(clusterv1.ReadyCondition)})
+		return u
+	}
+
+	tests := []struct {
+		name         string
+		deleting     bool
+		finalizers   []string
+		addChildren  func(objs *ObjectTree, node *Node)
+		wantDeletion *Deletion
+	}{
+		{
+			name:         "Object not being deleted",
+			deleting:     false,
+			finalizers:   []string{"machine.cluster.x-k8s.io"},
+			addChildren:  func(objs *ObjectTree, node *Node) {},
+			wantDeletion: nil,
+		},
+		{
+			name:        "Object being deleted with finalizers",
+			deleting:    true,
+			finalizers:  []string{"machine.cluster.x-k8s.io"},
+			addChildren: func(objs *ObjectTree, node *Node) {},
+			wantDeletion: &Deletion{
+				Timestamp:  deletionTimestamp,
+				Finalizers: []string{"machine.cluster.x-k8s.io"},
+			},
+		},
+		{
+			name:     "Object being deleted blocked by children",
+			deleting: true,
+			addChildren: func(objs *ObjectTree, node *Node) {
+				objs.Add(node, infraMachine("m1"), NoEcho(false))
+			},
+			wantDeletion: &Deletion{
+				Timestamp:       deletionTimestamp,
+				BlockingObjects: []string{"InfrastructureMachine/m1"},
+			},
+		},
+		{
+			name:     "Object being deleted blocked by hidden children",
+			deleting: true,
+			addChildren: func(objs *ObjectTree, node *Node) {
+				objs.Add(node, infraMachine("m1"), NoEcho(true))
+			},
+			wantDeletion: &Deletion{
+				Timestamp:       deletionTimestamp,
+				BlockingObjects: []string{"InfrastructureMachine/m1"},
+			},
+		},
+		{
+			name:     "Object being deleted blocked by the descendants of virtual children, including groups",
+			deleting: true,
+			addChildren: func(objs *ObjectTree, node *Node) {
+				workers := objs.AddVirtual(node, "Workers")
+				workers.Grouping = true
+				objs.Add(workers, testMachine("m2", conditions.TrueCondition(clusterv1.ReadyCondition)))
+				objs.Add(workers, testMachine("m1", conditions.TrueCondition(clusterv1.ReadyCondition)))
+				objs.Group()
+			},
+			wantDeletion: &Deletion{
+				Timestamp:       deletionTimestamp,
+				BlockingObjects: []string{"Machine/m1", "Machine/m2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := testCluster()
+			cluster.Finalizers = tt.finalizers
+			if tt.deleting {
+				cluster.DeletionTimestamp = &deletionTimestamp
+			}
+			conditions.MarkTrue(cluster, clusterv1.ReadyCondition)
+
+			objs := NewObjectTree(cluster, ObjectTreeOptions{})
+			tt.addChildren(objs, objs.GetRoot())
+
+			g.Expect(objs.GetDeletion(objs.GetRoot())).To(Equal(tt.wantDeletion))
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package status
+
+import (
+	"context"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// DiscoverOptions define options for the discovery process.
+type DiscoverOptions struct {
+	// ShowOtherConditions is a list of comma separated kind or kind/name for which we should set ShowConditions
+	// to signal to the presentation layer to show all the conditions for the objects.
+	ShowOtherConditions string
+
+	// DisableNoEcho disable hiding MachineInfrastructure or BootstrapConfig objects if the object's ready condition is true
+	// or it has the same Status, Severity and Reason of the parent's object ready condition (it is an echo)
+	DisableNoEcho bool
+
+	// DisableGroupObjects disable grouping machines objects in case the ready condition
+	// has the same Status, Severity and Reason
+	DisableGroupObjects bool
+
+	// GroupBy is the list of keys that machines must have in common in order to be grouped,
+	// e.g. reason or version; if empty, DefaultGroupBy is used.
+	GroupBy []string
+
+	// GroupingMessagePattern, if defined, is applied to the message of the ready condition when grouping
+	// by message; machines are grouped if the part of the message matching the pattern (or its first
+	// sub-match) is the same.
+	GroupingMessagePattern *regexp.Regexp
+
+	// GroupingMinSize is the minimum number of machines for creating a group; if lower than 2, 2 is used.
+	GroupingMinSize int
+
+	// GroupingParents is a list of kinds for which the children should be grouped; if empty,
+	// machines are grouped under the control plane and MachineDeployments.
+	GroupingParents []string
+
+	// ShowEvents enables reading the warning events for the objects in the tree.
+	ShowEvents bool
+
+	// OrganizeByFailureDomain organizes the machines under the control plane and MachineDeployments by failure domain,
+	// adding a virtual node with the readiness counts for each failure domain.
+	// NB. This is different from grouping machines by failure domain, see GroupByFailureDomain.
+	OrganizeByFailureDomain bool
+
+	// StuckThresholds defines how long a condition can be False or Unknown before being considered stuck;
+	// if nil, DefaultStuckThresholds is used.
+	StuckThresholds *StuckThresholds
+}
+
+func (d DiscoverOptions) toObjectTreeOptions() ObjectTreeOptions {
+	return ObjectTreeOptions{
+		ShowOtherConditions:    d.ShowOtherConditions,
+		DisableNoEcho:          d.DisableNoEcho,
+		DisableGroupObjects:    d.DisableGroupObjects,
+		GroupBy:                d.GroupBy,
+		GroupingMessagePattern: d.GroupingMessagePattern,
+		GroupingMinSize:        d.GroupingMinSize,
+		GroupingParents:        d.GroupingParents,
+	}
+}
+
+// Discovery returns an object tree representing the status of a Cluster API cluster.
+func Discovery(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, options DiscoverOptions) (*ObjectTree, error) {
+	objs := NewObjectTree(cluster, options.toObjectTreeOptions())
+	root := objs.GetRoot()
+
+	// NB. References that cannot be read are recorded on the referencing node; however, during the delete workflow
+	// it might be correct a ref does not exist...
+	clusterInfra := getRef(ctx, c, root, "spec.infrastructureRef", cluster.Spec.InfrastructureRef, cluster.Namespace)
+	if clusterInfra != nil {
+		objs.Add(root, clusterInfra, ObjectMetaName("ClusterInfrastructure"))
+	}
+
+	// NB. If the control plane object does not exist, control plane machines are added to the cluster.
+	controlPlaneNode := root
+	controlPLane := getRef(ctx, c, root, "spec.controlPlaneRef", cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	if controlPLane != nil {
+		controlPlaneNode = objs.Add(root, controlPLane, ObjectMetaName("ControlPlane"), GroupingObject(true))
+	}
+
+	machinesList, err := getMachinesInCluster(ctx, c, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+	machineMap := map[string]bool{}
+	addMachineFunc := func(parent *Node, m *clusterv1.Machine) {
+		if options.OrganizeByFailureDomain && parent != root && !parent.Virtual {
+			parent = objs.addToFailureDomain(parent, m)
+		}
+		machineNode := objs.Add(parent, m)
+		machineMap[m.Name] = true
+
+		machineInfra := getRef(ctx, c, machineNode, "spec.infrastructureRef", &m.Spec.InfrastructureRef, cluster.Namespace)
+		if machineInfra != nil {
+			objs.Add(machineNode, machineInfra, ObjectMetaName("MachineInfrastructure"), NoEcho(true))
+		}
+
+		machineBootstrap := getRef(ctx, c, machineNode, "spec.bootstrap.configRef", m.Spec.Bootstrap.ConfigRef, cluster.Namespace)
+		if machineBootstrap != nil {
+			objs.Add(machineNode, machineBootstrap, ObjectMetaName("BootstrapConfig"), NoEcho(true))
+		}
+	}
+
+	controlPlaneMachines := selectControlPlaneMachines(machinesList)
+	for i := range controlPlaneMachines {
+		cp := controlPlaneMachines[i]
+		addMachineFunc(controlPlaneNode, cp)
+	}
+	if controlPLane != nil {
+		controlPlaneNode.VersionRollout = newVersionRollout(controlPLane, controlPlaneMachines)
+	}
+
+	if len(machinesList.Items) == len(controlPlaneMachines) {
+		if err := completeDiscovery(ctx, c, cluster, objs, options); err != nil {
+			return nil, err
+		}
+		return objs, nil
+	}
+
+	workers := objs.AddVirtual(root, "Workers")
+
+	machinesDeploymentList, err := getMachineDeploymentsInCluster(ctx, c, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	machineSetList, err := getMachineSetsInCluster(ctx, c, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range machinesDeploymentList.Items {
+		md := &machinesDeploymentList.Items[i]
+		mdNode := objs.Add(workers, md, GroupingObject(true))
+
+		var mdMachines []*clusterv1.Machine
+		machineSets := selectMachinesSetsControlledBy(machineSetList, md)
+		for i := range machineSets {
+			ms := machineSets[i]
+
+			machines := selectMachinesControlledBy(machinesList, ms)
+			for _, w := range machines {
+				addMachineFunc(mdNode, w)
+			}
+			mdMachines = append(mdMachines, machines...)
+		}
+		mdNode.VersionRollout = newVersionRollout(md, mdMachines)
+	}
+
+	if len(machineMap) < len(machinesList.Items) {
+		other := objs.AddVirtual(workers, "Other")
+
+		for i := range machinesList.Items {
+			m := &machinesList.Items[i]
+			if _, ok := machineMap[m.Name]; ok {
+				continue
+			}
+			addMachineFunc(other, m)
+		}
+	}
+
+	if err := completeDiscovery(ctx, c, cluster, objs, options); err != nil {
+		return nil, err
+	}
+	return objs, nil
+}
+
+// completeDiscovery adds events, if requested, and completes the object tree once all the objects are added to the tree.
+// NB. Events are added before grouping, because grouping removes the descendants of grouped nodes.
+func completeDiscovery(ctx context.Context, c client.Client, cluster *clusterv1.Cluster, objs *ObjectTree, options DiscoverOptions) error {
+	if options.ShowEvents {
+		if err := discoverEvents(ctx, c, cluster.Namespace, objs); err != nil {
+			return err
+		}
+	}
+	objs.Complete(options.StuckThresholds)
+	return nil
+}
+
+// getRef returns the object referenced by a node, or nil if the reference is not set or the object cannot be read;
+// in the latter case, the reference is recorded in the node's MissingReferences.
+func getRef(ctx context.Context, c client.Client, node *Node, field string, ref *corev1.ObjectReference, namespace string) *unstructured.Unstructured {
+	if ref == nil {
+		return nil
+	}
+	obj, err := external.Get(ctx, c, ref, namespace)
+	if err != nil {
+		node.MissingReferences = append(node.MissingReferences, MissingReference{
+			Field: field,
+			Kind:  ref.Kind,
+			Name:  ref.Name,
+			Error: err.Error(),
+		})
+		return nil
+	}
+	return obj
+}
+
+func getMachinesInCluster(ctx context.Context, c client.Client, namespace, name string) (*clusterv1.MachineList, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	machineList := &clusterv1.MachineList{}
+	labels := map[string]string{clusterv1.ClusterLabelName: name}
+
+	if err := c.List(ctx, machineList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	return machineList, nil
+}
+
+func getMachineDeploymentsInCluster(ctx context.Context, c client.Client, namespace, name string) (*clusterv1.MachineDeploymentList, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	machineDeploymentList := &clusterv1.MachineDeploymentList{}
+	labels := map[string]string{clusterv1.ClusterLabelName: name}
+
+	if err := c.List(ctx, machineDeploymentList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	return machineDeploymentList, nil
+}
+
+func getMachineSetsInCluster(ctx context.Context, c client.Client, namespace, name string) (*clusterv1.MachineSetList, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	machineSetList := &clusterv1.MachineSetList{}
+	labels := map[string]string{clusterv1.ClusterLabelName: name}
+
+	if err := c.List(ctx, machineSetList, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	return machineSetList, nil
+}
+
+func selectControlPlaneMachines(machineList *clusterv1.MachineList) []*clusterv1.Machine {
+	machines := []*clusterv1.Machine{}
+	for i := range machineList.Items {
+		m := &machineList.Items[i]
+		if util.IsControlPlaneMachine(m) {
+			machines = append(machines, m)
+		}
+	}
+	return machines
+}
+
+func selectMachinesSetsControlledBy(machineSetList *clusterv1.MachineSetList, controller controllerutil.Object) []*clusterv1.MachineSet {
+	machineSets := []*clusterv1.MachineSet{}
+	for i := range machineSetList.Items {
+		m := &machineSetList.Items[i]
+		if util.IsControlledBy(m, controller) {
+			machineSets = append(machineSets, m)
+		}
+	}
+	return machineSets
+}
+
+func selectMachinesControlledBy(machineList *clusterv1.MachineList, controller controllerutil.Object) []*clusterv1.Machine {
+	machines := []*clusterv1.Machine{}
+	for i := range machineList.Items {
+		m := &machineList.Items[i]
+		if util.IsControlledBy(m, controller) {
+			machines = append(machines, m)
+		}
+	}
+	return machines
+}
@@ -0,0 +1,89 @@
+package status
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxEventsPerObject defines the max number of warning events to be kept for each object in the tree.
+const maxEventsPerObject = 3
+
+// discoverEvents reads the warning events in the namespace and attaches them to the corresponding
+// objects in the tree, keeping only the most recent ones.
+func discoverEvents(ctx context.Context, c client.Client, namespace string, objs *ObjectTree) error {
+	eventList := &corev1.EventList{}
+	if err := c.List(ctx, eventList, client.InNamespace(namespace)); err != nil {
+		return err
+	}
+	objs.attachEvents(eventList.Items)
+	return nil
+}
+
+// attachEvents attaches the warning events to the corresponding objects in the tree, keeping only the most recent ones.
+// NB. Events for the objects hidden because of the NoEcho option are attached to the parent of the hidden objects.
+// NB. attachEvents should be called before Group, so events for the grouped objects are carried into the group nodes.
+func (od ObjectTree) attachEvents(events []corev1.Event) {
+	targets := map[types.UID]*Node{}
+	od.Walk(func(node *Node, _ int) bool {
+		targets[node.ID] = node
+		for _, hidden := range node.HiddenChildren {
+			targets[hidden.ID] = node
+		}
+		return true
+	})
+
+	for i := range events {
+		e := events[i]
+		if e.Type != corev1.EventTypeWarning {
+			continue
+		}
+
+		// Skip events for objects not included in the tree.
+		node, ok := targets[e.InvolvedObject.UID]
+		if !ok {
+			continue
+		}
+		node.Events = append(node.Events, e)
+	}
+
+	od.Walk(func(node *Node, _ int) bool {
+		node.Events = mostRecentEvents(node.Events)
+		return true
+	})
+}
+
+// getSubtreeEvents returns the events of a node and of all its descendants.
+func (od ObjectTree) getSubtreeEvents(node *Node) []corev1.Event {
+	events := append([]corev1.Event{}, node.Events...)
+	for _, child := range od.GetChildren(node.ID) {
+		events = append(events, od.getSubtreeEvents(child)...)
+	}
+	return events
+}
+
+// mostRecentEvents returns the most recent events, sorted from the most recent one.
+func mostRecentEvents(events []corev1.Event) []corev1.Event {
+	sort.SliceStable(events, func(i, j int) bool {
+		return GetEventTime(events[i]).After(GetEventTime(events[j]))
+	})
+	if len(events) > maxEventsPerObject {
+		events = events[:maxEventsPerObject]
+	}
+	return events
+}
+
+// GetEventTime returns the last time an event was observed.
+func GetEventTime(e corev1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.FirstTimestamp.Time
+}
@@ -0,0 +1,133 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeAttachEvents(t *testing.T) {
+	now := time.Now()
+	event := func(name string, uid types.UID, eventType string, age time.Duration) corev1.Event {
+		return corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name},
+			InvolvedObject: corev1.ObjectReference{UID: uid},
+			Type:           eventType,
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		events     []corev1.Event
+		wantEvents map[types.UID][]string
+	}{
+		{
+			name: "Warning events are attached to the matching objects",
+			events: []corev1.Event{
+				event("e1", "m1", corev1.EventTypeWarning, time.Minute),
+				event("e2", "m2", corev1.EventTypeWarning, time.Minute),
+			},
+			wantEvents: map[types.UID][]string{"m1": {"e1"}, "m2": {"e2"}},
+		},
+		{
+			name: "Normal events and events for objects not in the tree are skipped",
+			events: []corev1.Event{
+				event("e1", "m1", corev1.EventTypeNormal, time.Minute),
+				event("e2", "other", corev1.EventTypeWarning, time.Minute),
+			},
+			wantEvents: map[types.UID][]string{},
+		},
+		{
+			name: "Only the 3 most recent events are kept, sorted from the most recent one",
+			events: []corev1.Event{
+				event("e1", "m1", corev1.EventTypeWarning, 4*time.Minute),
+				event("e2", "m1", corev1.EventTypeWarning, time.Minute),
+				event("e3", "m1", corev1.EventTypeWarning, 3*time.Minute),
+				event("e4", "m1", corev1.EventTypeWarning, 2*time.Minute),
+			},
+			wantEvents: map[types.UID][]string{"m1": {"e2", "e4", "e3"}},
+		},
+		{
+			name: "Events for hidden objects are attached to the parent",
+			events: []corev1.Event{
+				event("e1", "m1-infra", corev1.EventTypeWarning, time.Minute),
+				event("e2", "m1", corev1.EventTypeWarning, 2*time.Minute),
+			},
+			wantEvents: map[types.UID][]string{"m1": {"e1", "e2"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+			for _, name := range []string{"m1", "m2"} {
+				machineNode := objs.Add(objs.GetRoot(), testMachine(name, conditions.TrueCondition(clusterv1.ReadyCondition)))
+				infra := &unstructured.Unstructured{}
+				infra.SetKind("InfrastructureMachine")
+				infra.SetName(name)
+				infra.SetUID(types.UID(name + "-infra"))
+				conditions.UnstructuredSetter(infra).SetConditions(clusterv1.Conditions{*conditions.TrueCondition(clusterv1.ReadyCondition)})
+				objs.Add(machineNode, infra, NoEcho(true))
+			}
+
+			objs.attachEvents(tt.events)
+
+			got := map[types.UID][]string{}
+			objs.Walk(func(node *Node, _ int) bool {
+				if len(node.Events) > 0 {
+					got[node.ID] = eventNamesOf(node.Events)
+				}
+				return true
+			})
+			g.Expect(got).To(Equal(tt.wantEvents))
+		})
+	}
+}
+
+func Test_ObjectTreeGroupCarriesEvents(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	mdNode := objs.Add(objs.GetRoot(), testMachineDeployment("md"), GroupingObject(true))
+	readyFalse := conditions.FalseCondition(clusterv1.ReadyCondition, "InstanceNotReady", clusterv1.ConditionSeverityWarning, "")
+	for _, name := range []string{"m1", "m2"} {
+		machineNode := objs.Add(mdNode, testMachine(name, readyFalse))
+		// NB. The infrastructure machine is not hidden, so it is removed from the tree when the machines are grouped.
+		infra := &unstructured.Unstructured{}
+		infra.SetKind("InfrastructureMachine")
+		infra.SetName(name)
+		infra.SetUID(types.UID(name + "-infra"))
+		conditions.UnstructuredSetter(infra).SetConditions(clusterv1.Conditions{*conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, "")})
+		objs.Add(machineNode, infra, NoEcho(true))
+	}
+
+	objs.attachEvents([]corev1.Event{
+		{ObjectMeta: metav1.ObjectMeta{Name: "e1"}, InvolvedObject: corev1.ObjectReference{UID: "m1"}, Type: corev1.EventTypeWarning},
+		{ObjectMeta: metav1.ObjectMeta{Name: "e2"}, InvolvedObject: corev1.ObjectReference{UID: "m2"}, Type: corev1.EventTypeWarning},
+		{ObjectMeta: metav1.ObjectMeta{Name: "e3"}, InvolvedObject: corev1.ObjectReference{UID: "m1-infra"}, Type: corev1.EventTypeWarning},
+	})
+	objs.Group()
+
+	children := objs.GetChildren(mdNode.ID)
+	g.Expect(children).To(HaveLen(1))
+	g.Expect(children[0].IsGroup()).To(BeTrue())
+	g.Expect(eventNamesOf(children[0].Events)).To(ConsistOf("e1", "e2", "e3"))
+}
+
+// eventNamesOf returns the names of the events.
+func eventNamesOf(events []corev1.Event) []string {
+	var names []string
+	for _, e := range events {
+		names = append(names, e.Name)
+	}
+	return names
+}
@@ -0,0 +1,140 @@
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+// Explanation provides a description of a condition reason or type, and a suggestion about how to move forward.
+type Explanation struct {
+	// Description of what the reason or the condition type means.
+	Description string
+
+	// Hint about the next steps to be taken to investigate or fix the problem.
+	Hint string
+}
+
+// reasonExplanations is the knowledge base for well-known condition reasons used by Cluster API and providers.
+var reasonExplanations = map[string]Explanation{
+	clusterv1.WaitingForInfrastructureFallbackReason: {
+		Description: "The infrastructure object is not ready yet, and it does not report a ready condition.",
+		Hint:        "Check the infrastructure object and the logs of the infrastructure provider.",
+	},
+	clusterv1.WaitingForControlPlaneFallbackReason: {
+		Description: "The control plane object is not ready yet, and it does not report a ready condition.",
+		Hint:        "Check the control plane object and the logs of the control plane provider.",
+	},
+	clusterv1.WaitingForDataSecretFallbackReason: {
+		Description: "The bootstrap data secret for the machine has not been generated yet.",
+		Hint:        "Check the bootstrap config and the logs of the bootstrap provider.",
+	},
+	clusterv1.MachineHasFailure: {
+		Description: "The machine reports a terminal failure, and it won't be reconciled anymore.",
+		Hint:        "Check failureReason and failureMessage; the machine should be deleted and replaced.",
+	},
+	clusterv1.NodeNotFound: {
+		Description: "The node for the machine was observed before, but now it is gone.",
+		Hint:        "Check if the node was deleted; the machine is going to be remediated.",
+	},
+	clusterv1.NodeStartupTimeout: {
+		Description: "The node for the machine did not join the cluster within the MachineHealthCheck timeout.",
+		Hint:        "Check the cloud-init/bootstrap logs on the machine and the connectivity to the API server.",
+	},
+	clusterv1.UnhealthyNodeCondition: {
+		Description: "The node for the machine reports one of the unhealthy conditions of the MachineHealthCheck.",
+		Hint:        "Check the node conditions with kubectl describe node on the workload cluster.",
+	},
+	clusterv1.WaitingForRemediation: {
+		Description: "The machine failed a health check, and it is waiting for the owner controller to remediate it.",
+		Hint:        "Check the owner of the machine, e.g. the MachineSet, and the maxUnhealthy setting of the MachineHealthCheck.",
+	},
+	"DrainingFailed": {
+		Description: "The machine is being deleted, but draining the node failed.",
+		Hint:        "Check PodDisruptionBudgets and pods that cannot be evicted from the node.",
+	},
+	"WaitingForClusterInfrastructure": {
+		Description: "The object is waiting for the cluster infrastructure, e.g. networks and load balancers, to be ready.",
+		Hint:        "Check the InfrastructureReady condition of the cluster.",
+	},
+	"WaitingForControlPlaneAvailable": {
+		Description: "Worker machines are waiting for the control plane to be initialized before joining the cluster.",
+		Hint:        "Check the control plane machines.",
+	},
+	"WaitingForKubeadmInit": {
+		Description: "The control plane is waiting for the first control plane machine to complete kubeadm init.",
+		Hint:        "Check the cloud-init/bootstrap logs on the first control plane machine.",
+	},
+	"DataSecretGenerationFailed": {
+		Description: "The bootstrap provider failed to generate the bootstrap data secret.",
+		Hint:        "Check the bootstrap config spec and the logs of the bootstrap provider.",
+	},
+	"CertificatesGenerationFailed": {
+		Description: "The cluster certificates could not be generated or retrieved.",
+		Hint:        "Check the certificate secrets for the cluster and the logs of the control plane provider.",
+	},
+	"CertificatesCorrupted": {
+		Description: "The cluster certificates stored in secrets are corrupted.",
+		Hint:        "Check the certificate secrets for the cluster; they must be restored from a backup.",
+	},
+	"RollingUpdateInProgress": {
+		Description: "Machines are being replaced because the machine spec changed, e.g. for a version upgrade.",
+		Hint:        "Wait for the rollout to complete; use --upgrade to see the progress.",
+	},
+	"ScalingUp": {
+		Description: "The number of replicas is lower than the desired number of replicas.",
+		Hint:        "Wait for new machines to be provisioned; check the machines if this takes too long.",
+	},
+	"ScalingDown": {
+		Description: "The number of replicas is higher than the desired number of replicas.",
+		Hint:        "Wait for machines to be deleted; check the machines being deleted if this takes too long.",
+	},
+	"InstanceProvisionFailed": {
+		Description: "The infrastructure provider failed to create the instance for the machine.",
+		Hint:        "Check the condition message and the quota and permissions on the infrastructure.",
+	},
+	"WaitingForBootstrapData": {
+		Description: "The infrastructure object is waiting for the bootstrap data secret before creating the instance.",
+		Hint:        "Check the BootstrapReady condition of the machine.",
+	},
+}
+
+// conditionTypeExplanations is the knowledge base for well-known condition types, used when the reason of a condition
+// is not in the knowledge base.
+var conditionTypeExplanations = map[clusterv1.ConditionType]Explanation{
+	clusterv1.InfrastructureReadyCondition: {
+		Description: "The infrastructure object for the cluster or the machine is not ready.",
+		Hint:        "Check the infrastructure object and the logs of the infrastructure provider.",
+	},
+	clusterv1.ControlPlaneReadyCondition: {
+		Description: "The control plane object for the cluster is not ready.",
+		Hint:        "Check the control plane object and the control plane machines.",
+	},
+	clusterv1.BootstrapReadyCondition: {
+		Description: "The bootstrap object for the machine is not ready.",
+		Hint:        "Check the bootstrap config and the logs of the bootstrap provider.",
+	},
+	clusterv1.MachineHealthCheckSuccededCondition: {
+		Description: "The machine failed a MachineHealthCheck.",
+		Hint:        "Check the node for the machine in the workload cluster.",
+	},
+	clusterv1.MachineOwnerRemediatedCondition: {
+		Description: "The machine failed a health check, and it is waiting for remediation.",
+		Hint:        "Check the owner of the machine, e.g. the MachineSet.",
+	},
+}
+
+// Explain returns the explanation for a condition not true, looking up the condition reason first and then
+// the condition type in the knowledge base; nil is returned for true conditions or if no explanation is available.
+// NB. Reasons with a source reference, e.g. WaitingForInfrastructure@Machine/m1, are looked up without the reference.
+func Explain(c *clusterv1.Condition) *Explanation {
+	if c == nil || c.Status == corev1.ConditionTrue {
+		return nil
+	}
+	if e, ok := reasonExplanations[trimSourceRef(c.Reason)]; ok {
+		return &e
+	}
+	if e, ok := conditionTypeExplanations[c.Type]; ok {
+		return &e
+	}
+	return nil
+}
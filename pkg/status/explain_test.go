@@ -0,0 +1,54 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_Explain(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition *clusterv1.Condition
+		want      *Explanation
+	}{
+		{
+			name:      "True condition",
+			condition: conditions.TrueCondition(clusterv1.ReadyCondition),
+			want:      nil,
+		},
+		{
+			name:      "Well-known reason",
+			condition: conditions.FalseCondition(clusterv1.ReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason, clusterv1.ConditionSeverityInfo, ""),
+			want:      explanationFor(reasonExplanations[clusterv1.WaitingForInfrastructureFallbackReason]),
+		},
+		{
+			name:      "Well-known reason with source reference",
+			condition: conditions.FalseCondition(clusterv1.ReadyCondition, "DrainingFailed@Machine/m1", clusterv1.ConditionSeverityWarning, ""),
+			want:      explanationFor(reasonExplanations["DrainingFailed"]),
+		},
+		{
+			name:      "Unknown reason, well-known condition type",
+			condition: conditions.FalseCondition(clusterv1.BootstrapReadyCondition, "Foo", clusterv1.ConditionSeverityInfo, ""),
+			want:      explanationFor(conditionTypeExplanations[clusterv1.BootstrapReadyCondition]),
+		},
+		{
+			name:      "Unknown reason and condition type",
+			condition: conditions.FalseCondition("Foo", "Bar", clusterv1.ConditionSeverityInfo, ""),
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(Explain(tt.condition)).To(Equal(tt.want))
+		})
+	}
+}
+
+func explanationFor(e Explanation) *Explanation {
+	return &e
+}
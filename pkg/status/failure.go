@@ -0,0 +1,57 @@
+package status
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Failure describes a terminal failure reported in the status of an object by FailureReason and FailureMessage.
+type Failure struct {
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetFailure returns the terminal failure for an object, if any.
+func GetFailure(obj controllerutil.Object) *Failure {
+	var reason, message string
+	switch o := obj.(type) {
+	case *clusterv1.Cluster:
+		if o.Status.FailureReason != nil {
+			reason = string(*o.Status.FailureReason)
+		}
+		if o.Status.FailureMessage != nil {
+			message = *o.Status.FailureMessage
+		}
+	case *clusterv1.Machine:
+		if o.Status.FailureReason != nil {
+			reason = string(*o.Status.FailureReason)
+		}
+		if o.Status.FailureMessage != nil {
+			message = *o.Status.FailureMessage
+		}
+	case *clusterv1.MachineSet:
+		if o.Status.FailureReason != nil {
+			reason = string(*o.Status.FailureReason)
+		}
+		if o.Status.FailureMessage != nil {
+			message = *o.Status.FailureMessage
+		}
+	case *unstructured.Unstructured:
+		reason, _, _ = unstructured.NestedString(o.Object, "status", "failureReason")
+		message, _, _ = unstructured.NestedString(o.Object, "status", "failureMessage")
+	}
+
+	if reason == "" && message == "" {
+		return nil
+	}
+	return &Failure{
+		Reason:  reason,
+		Message: message,
+	}
+}
+
+// IsFailed returns true if the object reports a terminal failure.
+func IsFailed(obj controllerutil.Object) bool {
+	return GetFailure(obj) != nil
+}
@@ -0,0 +1,81 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	capierrors "sigs.k8s.io/cluster-api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_GetFailure(t *testing.T) {
+	failedMachine := func(reason *capierrors.MachineStatusError, message *string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			Status: clusterv1.MachineStatus{
+				FailureReason:  reason,
+				FailureMessage: message,
+			},
+		}
+	}
+	infraMachine := func(fields map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		for k, v := range fields {
+			_ = unstructured.SetNestedField(u.Object, v, "status", k)
+		}
+		return u
+	}
+	reason := capierrors.CreateMachineError
+	message := "failed to create the VM"
+
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want *Failure
+	}{
+		{
+			name: "Typed machine without failure",
+			obj:  failedMachine(nil, nil),
+			want: nil,
+		},
+		{
+			name: "Typed machine with failure reason and message",
+			obj:  failedMachine(&reason, &message),
+			want: &Failure{Reason: string(reason), Message: message},
+		},
+		{
+			name: "Typed machine with failure message only",
+			obj:  failedMachine(nil, &message),
+			want: &Failure{Message: message},
+		},
+		{
+			name: "Unstructured object without failure",
+			obj:  infraMachine(map[string]interface{}{"ready": true}),
+			want: nil,
+		},
+		{
+			name: "Unstructured object with failure reason and message",
+			obj:  infraMachine(map[string]interface{}{"failureReason": "CreateError", "failureMessage": message}),
+			want: &Failure{Reason: "CreateError", Message: message},
+		},
+		{
+			name: "Unstructured object with failure reason only",
+			obj:  infraMachine(map[string]interface{}{"failureReason": "CreateError"}),
+			want: &Failure{Reason: "CreateError"},
+		},
+		{
+			name: "Object without failure fields",
+			obj:  &clusterv1.MachineDeployment{},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetFailure(tt.obj)).To(Equal(tt.want))
+			g.Expect(IsFailed(tt.obj)).To(Equal(tt.want != nil))
+		})
+	}
+}
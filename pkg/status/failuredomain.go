@@ -0,0 +1,47 @@
+package status
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// FailureDomainKind is the kind of the virtual nodes organizing machines by failure domain.
+const FailureDomainKind = "FailureDomain"
+
+// FailureDomainStats provides the readiness counts for the machines in a failure domain.
+type FailureDomainStats struct {
+	Ready int `json:"ready"`
+	Total int `json:"total"`
+}
+
+// addToFailureDomain returns the node the machine should be added to when organizing machines by failure domain,
+// that is a virtual node for the machine's failure domain under parent, and updates its readiness counts.
+// NB. Machines without a failure domain are added directly to parent.
+func (od ObjectTree) addToFailureDomain(parent *Node, m *clusterv1.Machine) *Node {
+	if m.Spec.FailureDomain == nil || *m.Spec.FailureDomain == "" {
+		return parent
+	}
+	failureDomain := *m.Spec.FailureDomain
+
+	id := types.UID(fmt.Sprintf("%s, %s/%s", parent.ID, FailureDomainKind, failureDomain))
+	fdNode := od.GetNode(id)
+	if fdNode == nil {
+		fdNode = newVirtualNode(parent.Namespace, failureDomain)
+		fdNode.ID = id
+		fdNode.Kind = FailureDomainKind
+		fdNode.FailureDomain = failureDomain
+		fdNode.FailureDomainStats = &FailureDomainStats{}
+		fdNode = od.add(parent, fdNode)
+		// NB. Machines in a failure domain are grouped if they would have been grouped under parent.
+		fdNode.Grouping = parent.Grouping
+	}
+
+	fdNode.FailureDomainStats.Total++
+	if conditions.IsTrue(m, clusterv1.ReadyCondition) {
+		fdNode.FailureDomainStats.Ready++
+	}
+	return fdNode
+}
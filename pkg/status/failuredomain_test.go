@@ -0,0 +1,83 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeAddToFailureDomain(t *testing.T) {
+	g := NewWithT(t)
+
+	readyTrue := conditions.TrueCondition(clusterv1.ReadyCondition)
+	readyFalse := conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, "")
+
+	fd1, fd2 := "fd1", "fd2"
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	mdNode := objs.Add(objs.GetRoot(), testMachineDeployment("md"), GroupingObject(true))
+	for _, m := range []struct {
+		name          string
+		failureDomain *string
+		ready         *clusterv1.Condition
+	}{
+		{name: "m1", failureDomain: &fd1, ready: readyTrue},
+		{name: "m2", failureDomain: &fd1, ready: readyFalse},
+		{name: "m3", failureDomain: &fd2, ready: readyTrue},
+		{name: "m4", failureDomain: nil, ready: readyTrue},
+	} {
+		machine := testMachine(m.name, m.ready)
+		machine.Spec.FailureDomain = m.failureDomain
+		objs.Add(objs.addToFailureDomain(mdNode, machine), machine)
+	}
+
+	stats := map[string]FailureDomainStats{}
+	var machines []string
+	for _, child := range objs.GetChildren(mdNode.ID) {
+		if child.Kind != FailureDomainKind {
+			machines = append(machines, child.Name)
+			continue
+		}
+		g.Expect(child.Virtual).To(BeTrue())
+		g.Expect(child.Grouping).To(BeTrue())
+		g.Expect(child.FailureDomainStats).ToNot(BeNil())
+		stats[child.Name] = *child.FailureDomainStats
+		g.Expect(objs.GetChildren(child.ID)).To(HaveLen(child.FailureDomainStats.Total))
+	}
+	g.Expect(machines).To(ConsistOf("m4"))
+	g.Expect(stats).To(Equal(map[string]FailureDomainStats{
+		"fd1": {Ready: 1, Total: 2},
+		"fd2": {Ready: 1, Total: 1},
+	}))
+}
+
+func Test_ObjectTreeGroupKeepsFailureDomains(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	mdNode := objs.Add(objs.GetRoot(), testMachineDeployment("md"), GroupingObject(true))
+	for _, failureDomain := range []string{"fd1", "fd2", "fd3"} {
+		for _, name := range []string{"m1", "m2"} {
+			failureDomain := failureDomain
+			machine := testMachine(failureDomain+"-"+name, conditions.TrueCondition(clusterv1.ReadyCondition))
+			machine.Spec.FailureDomain = &failureDomain
+			objs.Add(objs.addToFailureDomain(mdNode, machine), machine)
+		}
+	}
+	objs.RollUp()
+	objs.Group()
+
+	children := objs.GetChildren(mdNode.ID)
+	g.Expect(children).To(HaveLen(3))
+	for _, child := range children {
+		g.Expect(child.Kind).To(Equal(FailureDomainKind))
+		g.Expect(child.FailureDomainStats).To(Equal(&FailureDomainStats{Ready: 2, Total: 2}))
+
+		// NB. Machines in each failure domain are still grouped.
+		machines := objs.GetChildren(child.ID)
+		g.Expect(machines).To(HaveLen(1))
+		g.Expect(machines[0].IsGroup()).To(BeTrue())
+		g.Expect(machines[0].GroupItems).To(Equal([]string{child.Name + "-m1", child.Name + "-m2"}))
+	}
+}
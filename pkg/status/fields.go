@@ -0,0 +1,142 @@
+package status
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Replicas describes the ready and the desired replicas for an object, e.g. a MachineDeployment.
+type Replicas struct {
+	Ready   int64
+	Desired int64
+}
+
+// GetPhase returns the phase for an object, if defined.
+func GetPhase(obj controllerutil.Object) string {
+	switch o := obj.(type) {
+	case *clusterv1.Cluster:
+		return o.Status.Phase
+	case *clusterv1.Machine:
+		return o.Status.Phase
+	case *clusterv1.MachineDeployment:
+		return o.Status.Phase
+	case *unstructured.Unstructured:
+		phase, _, _ := unstructured.NestedString(o.Object, "status", "phase")
+		return phase
+	}
+	return ""
+}
+
+// GetReplicas returns the ready and the desired replicas for an object, e.g. a MachineDeployment or
+// a KubeadmControlPlane, if defined.
+func GetReplicas(obj controllerutil.Object) *Replicas {
+	switch o := obj.(type) {
+	case *clusterv1.MachineDeployment:
+		if o.Spec.Replicas == nil {
+			return nil
+		}
+		return &Replicas{
+			Ready:   int64(o.Status.ReadyReplicas),
+			Desired: int64(*o.Spec.Replicas),
+		}
+	case *clusterv1.MachineSet:
+		if o.Spec.Replicas == nil {
+			return nil
+		}
+		return &Replicas{
+			Ready:   int64(o.Status.ReadyReplicas),
+			Desired: int64(*o.Spec.Replicas),
+		}
+	case *unstructured.Unstructured:
+		desired, ok, err := unstructured.NestedInt64(o.Object, "spec", "replicas")
+		if err != nil || !ok {
+			return nil
+		}
+		ready, _, _ := unstructured.NestedInt64(o.Object, "status", "readyReplicas")
+		return &Replicas{
+			Ready:   ready,
+			Desired: desired,
+		}
+	}
+	return nil
+}
+
+// GetNodeRef returns the name of the node for a machine, if defined.
+func GetNodeRef(obj controllerutil.Object) string {
+	if m, ok := obj.(*clusterv1.Machine); ok && m.Status.NodeRef != nil {
+		return m.Status.NodeRef.Name
+	}
+	return ""
+}
+
+// GetProviderID returns the provider ID for a machine or an infrastructure machine, if defined.
+func GetProviderID(obj controllerutil.Object) string {
+	switch o := obj.(type) {
+	case *clusterv1.Machine:
+		if o.Spec.ProviderID != nil {
+			return *o.Spec.ProviderID
+		}
+	case *unstructured.Unstructured:
+		providerID, _, _ := unstructured.NestedString(o.Object, "spec", "providerID")
+		return providerID
+	}
+	return ""
+}
+
+// GetFailureDomain returns the failure domain for a machine, if defined.
+func GetFailureDomain(obj controllerutil.Object) string {
+	if m, ok := obj.(*clusterv1.Machine); ok && m.Spec.FailureDomain != nil {
+		return *m.Spec.FailureDomain
+	}
+	return ""
+}
+
+// GetObservedGeneration returns the latest generation observed by the controller reconciling an object,
+// or false if the object does not report the observed generation.
+func GetObservedGeneration(obj controllerutil.Object) (int64, bool) {
+	switch o := obj.(type) {
+	case *clusterv1.Cluster:
+		return o.Status.ObservedGeneration, true
+	case *clusterv1.Machine:
+		return o.Status.ObservedGeneration, true
+	case *clusterv1.MachineDeployment:
+		return o.Status.ObservedGeneration, true
+	case *clusterv1.MachineSet:
+		return o.Status.ObservedGeneration, true
+	case *unstructured.Unstructured:
+		observedGeneration, ok, err := unstructured.NestedInt64(o.Object, "status", "observedGeneration")
+		if err == nil && ok {
+			return observedGeneration, true
+		}
+		// NB. If the object does not report the observed generation, the oldest observed generation of
+		// the conditions in the Kubernetes standard format (metav1.Condition) is used, if any.
+		found := false
+		for _, g := range getStandardConditionsObservedGeneration(o) {
+			if !found || g < observedGeneration {
+				observedGeneration = g
+			}
+			found = true
+		}
+		return observedGeneration, found
+	}
+	return 0, false
+}
+
+// IsStale returns true if the status of an object was not reconciled for the latest generation of the object,
+// and so the object's conditions could be misleading.
+// NB. Objects not reporting the observed generation are never considered stale.
+func IsStale(obj controllerutil.Object) bool {
+	observedGeneration, ok := GetObservedGeneration(obj)
+	return ok && observedGeneration < obj.GetGeneration()
+}
+
+// IsPaused returns true if the reconciliation of an object is paused, because of the paused annotation
+// or, for clusters, because of Spec.Paused.
+func IsPaused(obj controllerutil.Object) bool {
+	if c, ok := obj.(*clusterv1.Cluster); ok && c.Spec.Paused {
+		return true
+	}
+	_, ok := obj.GetAnnotations()[clusterv1.PausedAnnotation]
+	return ok
+}
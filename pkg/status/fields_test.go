@@ -0,0 +1,204 @@
+package status
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_GetPhase(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want string
+	}{
+		{name: "Nil object", obj: nil, want: ""},
+		{name: "Typed cluster", obj: &clusterv1.Cluster{Status: clusterv1.ClusterStatus{Phase: "Provisioned"}}, want: "Provisioned"},
+		{name: "Typed machine", obj: &clusterv1.Machine{Status: clusterv1.MachineStatus{Phase: "Running"}}, want: "Running"},
+		{name: "Typed MachineDeployment", obj: &clusterv1.MachineDeployment{Status: clusterv1.MachineDeploymentStatus{Phase: "ScalingUp"}}, want: "ScalingUp"},
+		{name: "Typed object without phase", obj: &clusterv1.MachineSet{}, want: ""},
+		{name: "Unstructured object", obj: unstructuredWithFields(map[string]interface{}{"status.phase": "Running"}), want: "Running"},
+		{name: "Unstructured object without phase", obj: unstructuredWithFields(nil), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetPhase(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_GetReplicas(t *testing.T) {
+	replicas := int32(3)
+
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want *Replicas
+	}{
+		{name: "Nil object", obj: nil, want: nil},
+		{
+			name: "Typed MachineDeployment",
+			obj: &clusterv1.MachineDeployment{
+				Spec:   clusterv1.MachineDeploymentSpec{Replicas: &replicas},
+				Status: clusterv1.MachineDeploymentStatus{ReadyReplicas: 2},
+			},
+			want: &Replicas{Ready: 2, Desired: 3},
+		},
+		{name: "Typed MachineDeployment without replicas", obj: &clusterv1.MachineDeployment{}, want: nil},
+		{
+			name: "Typed MachineSet",
+			obj: &clusterv1.MachineSet{
+				Spec:   clusterv1.MachineSetSpec{Replicas: &replicas},
+				Status: clusterv1.MachineSetStatus{ReadyReplicas: 3},
+			},
+			want: &Replicas{Ready: 3, Desired: 3},
+		},
+		{name: "Typed object without replicas", obj: &clusterv1.Machine{}, want: nil},
+		{
+			name: "Unstructured object",
+			obj:  unstructuredWithFields(map[string]interface{}{"spec.replicas": int64(3), "status.readyReplicas": int64(1)}),
+			want: &Replicas{Ready: 1, Desired: 3},
+		},
+		{
+			name: "Unstructured object without ready replicas",
+			obj:  unstructuredWithFields(map[string]interface{}{"spec.replicas": int64(3)}),
+			want: &Replicas{Ready: 0, Desired: 3},
+		},
+		{name: "Unstructured object without replicas", obj: unstructuredWithFields(nil), want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetReplicas(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_GetNodeRef(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want string
+	}{
+		{name: "Nil object", obj: nil, want: ""},
+		{name: "Machine with node ref", obj: &clusterv1.Machine{Status: clusterv1.MachineStatus{NodeRef: &corev1.ObjectReference{Name: "node1"}}}, want: "node1"},
+		{name: "Machine without node ref", obj: &clusterv1.Machine{}, want: ""},
+		{name: "Unstructured object", obj: unstructuredWithFields(map[string]interface{}{"status.nodeRef.name": "node1"}), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetNodeRef(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_GetProviderID(t *testing.T) {
+	providerID := "aws:///us-east-1a/i-1234"
+
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want string
+	}{
+		{name: "Nil object", obj: nil, want: ""},
+		{name: "Machine with provider ID", obj: &clusterv1.Machine{Spec: clusterv1.MachineSpec{ProviderID: &providerID}}, want: providerID},
+		{name: "Machine without provider ID", obj: &clusterv1.Machine{}, want: ""},
+		{name: "Unstructured object", obj: unstructuredWithFields(map[string]interface{}{"spec.providerID": providerID}), want: providerID},
+		{name: "Unstructured object without provider ID", obj: unstructuredWithFields(nil), want: ""},
+		{name: "Object without provider ID", obj: &clusterv1.Cluster{}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(GetProviderID(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+// unstructuredWithFields returns an unstructured object with the given fields, using dot separated paths as keys.
+func unstructuredWithFields(fields map[string]interface{}) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for path, v := range fields {
+		_ = unstructured.SetNestedField(u.Object, v, strings.Split(path, ".")...)
+	}
+	return u
+}
+
+func Test_IsStale(t *testing.T) {
+	machine := func(generation, observedGeneration int64) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			ObjectMeta: metav1.ObjectMeta{Generation: generation},
+			Status:     clusterv1.MachineStatus{ObservedGeneration: observedGeneration},
+		}
+	}
+	infraMachine := func(generation int64, observedGeneration interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetGeneration(generation)
+		if observedGeneration != nil {
+			_ = unstructured.SetNestedField(u.Object, observedGeneration, "status", "observedGeneration")
+		}
+		return u
+	}
+	addon := func(generation int64, conditionsObservedGeneration ...int64) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		u.SetGeneration(generation)
+		var conditions []interface{}
+		for _, g := range conditionsObservedGeneration {
+			conditions = append(conditions, map[string]interface{}{"type": "Available", "status": "True", "observedGeneration": g})
+		}
+		_ = unstructured.SetNestedSlice(u.Object, conditions, "status", "conditions")
+		return u
+	}
+
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want bool
+	}{
+		{name: "Typed object reconciled", obj: machine(2, 2), want: false},
+		{name: "Typed object not reconciled", obj: machine(3, 2), want: true},
+		{name: "Unstructured object reconciled", obj: infraMachine(2, int64(2)), want: false},
+		{name: "Unstructured object not reconciled", obj: infraMachine(3, int64(2)), want: true},
+		{name: "Unstructured object without observed generation", obj: infraMachine(3, nil), want: false},
+		{name: "Unstructured object with standard conditions reconciled", obj: addon(2, 2, 2), want: false},
+		{name: "Unstructured object with standard conditions not reconciled", obj: addon(3, 3, 2), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsStale(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_IsPaused(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want bool
+	}{
+		{name: "Not paused", obj: &clusterv1.Machine{}, want: false},
+		{name: "Paused cluster", obj: &clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Paused: true}}, want: true},
+		{name: "Paused annotation", obj: &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{clusterv1.PausedAnnotation: ""}}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsPaused(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
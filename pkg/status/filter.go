@@ -0,0 +1,235 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+// filterOperators is the list of supported operators; longer operators must come first
+// so e.g. >= is not parsed as >.
+var filterOperators = []string{">=", "<=", "!=", "=", ">", "<", "~"}
+
+// filterKeys defines the keys supported in filter expressions, and whether they support ordering operators
+// instead of the regular expression match.
+var filterKeys = map[string]bool{
+	"kind":     false,
+	"name":     false,
+	"status":   false,
+	"severity": true,
+	"reason":   false,
+	"message":  false,
+	"age":      true,
+}
+
+// severityOrder defines the order of severities when comparing them, with None for conditions
+// without severity (e.g. Ready=True) being the lowest.
+var severityOrder = map[clusterv1.ConditionSeverity]int{
+	clusterv1.ConditionSeverityNone:    0,
+	clusterv1.ConditionSeverityInfo:    1,
+	clusterv1.ConditionSeverityWarning: 2,
+	clusterv1.ConditionSeverityError:   3,
+}
+
+// Filter is an expression selecting nodes in the object tree, e.g. "kind=Machine,severity>=Warning";
+// a node matches the filter if it matches all of its comma separated terms.
+//
+// Terms have the form <key><operator><value>, where key is one of kind, name, status, severity, reason, message
+// or age, and operator is one of =, != and ~ (regular expression match) or, for severity and age, =, !=, >, >=, <
+// and <=.
+// Status, severity, reason, message and age refer to the ready condition, with age being the time since
+// the last transition.
+type Filter struct {
+	expr  string
+	terms []filterTerm
+}
+
+type filterTerm struct {
+	key      string
+	operator string
+	value    string
+	regex    *regexp.Regexp
+	severity int
+	age      time.Duration
+}
+
+// ParseFilter parses a filter expression.
+func ParseFilter(expr string) (*Filter, error) {
+	f := &Filter{expr: expr}
+	for _, t := range strings.Split(expr, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		term, err := parseFilterTerm(t)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter %q: %v", expr, err)
+		}
+		f.terms = append(f.terms, term)
+	}
+	if len(f.terms) == 0 {
+		return nil, fmt.Errorf("invalid filter %q: no terms", expr)
+	}
+	return f, nil
+}
+
+func parseFilterTerm(t string) (filterTerm, error) {
+	// Find the first operator in the term; in case of operators at the same position, the longest wins.
+	term := filterTerm{}
+	pos := -1
+	for _, op := range filterOperators {
+		if i := strings.Index(t, op); i > 0 && (pos == -1 || i < pos) {
+			pos = i
+			term.operator = op
+		}
+	}
+	if term.operator == "" {
+		return term, fmt.Errorf("%q does not have a valid operator", t)
+	}
+	term.key = strings.ToLower(strings.TrimSpace(t[:pos]))
+	term.value = strings.TrimSpace(t[pos+len(term.operator):])
+
+	ordered, ok := filterKeys[term.key]
+	if !ok {
+		return term, fmt.Errorf("%q is not a valid key", term.key)
+	}
+	isOrderOperator := term.operator != "=" && term.operator != "!=" && term.operator != "~"
+	if (isOrderOperator && !ordered) || (term.operator == "~" && ordered) {
+		return term, fmt.Errorf("operator %q is not supported for %q", term.operator, term.key)
+	}
+
+	switch {
+	case term.operator == "~":
+		regex, err := regexp.Compile(term.value)
+		if err != nil {
+			return term, err
+		}
+		term.regex = regex
+	case term.key == "severity":
+		severity, ok := getSeverityOrder(term.value)
+		if !ok {
+			return term, fmt.Errorf("%q is not a valid severity", term.value)
+		}
+		term.severity = severity
+	case term.key == "age":
+		age, err := time.ParseDuration(term.value)
+		if err != nil {
+			return term, err
+		}
+		term.age = age
+	}
+	return term, nil
+}
+
+func getSeverityOrder(value string) (int, bool) {
+	for s, order := range severityOrder {
+		if strings.EqualFold(string(s), value) || (s == clusterv1.ConditionSeverityNone && strings.EqualFold(value, "None")) {
+			return order, true
+		}
+	}
+	return 0, false
+}
+
+// String returns the filter expression.
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Match returns true if the node matches all the terms of the filter.
+func (f *Filter) Match(node *Node) bool {
+	for _, t := range f.terms {
+		if !t.match(node) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t filterTerm) match(node *Node) bool {
+	ready := node.GetReadyCondition()
+
+	switch t.key {
+	case "kind":
+		return t.matchString(node.Kind, true)
+	case "name":
+		// NB. A group node matches if any of the objects in the group matches.
+		if node.IsGroup() {
+			for _, item := range node.GroupItems {
+				if t.matchString(item, false) {
+					return t.operator != "!="
+				}
+			}
+			return t.operator == "!="
+		}
+		return t.matchString(node.Name, false)
+	case "status":
+		if ready == nil {
+			return t.matchString("", true)
+		}
+		return t.matchString(string(ready.Status), true)
+	case "severity":
+		severity := ""
+		if ready != nil {
+			severity = string(ready.Severity)
+		}
+		order, _ := getSeverityOrder(severity)
+		return compare(int64(order-t.severity), t.operator)
+	case "reason":
+		if ready == nil {
+			return t.matchString("", false)
+		}
+		return t.matchString(ready.Reason, false)
+	case "message":
+		if ready == nil {
+			return t.matchString("", false)
+		}
+		return t.matchString(ready.Message, false)
+	case "age":
+		if ready == nil || ready.LastTransitionTime.IsZero() {
+			return false
+		}
+		age := time.Since(ready.LastTransitionTime.Time)
+		return compare(int64(age-t.age), t.operator)
+	}
+	return false
+}
+
+func (t filterTerm) matchString(value string, ignoreCase bool) bool {
+	switch t.operator {
+	case "~":
+		return t.regex.MatchString(value)
+	case "!=":
+		return !equal(value, t.value, ignoreCase)
+	default:
+		return equal(value, t.value, ignoreCase)
+	}
+}
+
+func equal(a, b string, ignoreCase bool) bool {
+	if ignoreCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// compare returns the result of an operator given the sign of the difference between two values.
+func compare(diff int64, operator string) bool {
+	switch operator {
+	case "=":
+		return diff == 0
+	case "!=":
+		return diff != 0
+	case ">":
+		return diff > 0
+	case ">=":
+		return diff >= 0
+	case "<":
+		return diff < 0
+	case "<=":
+		return diff <= 0
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		wantErr bool
+	}{
+		{
+			name: "Single term",
+			expr: "kind=Machine",
+		},
+		{
+			name: "Many terms",
+			expr: "kind=Machine, severity>=Warning,age>30m",
+		},
+		{
+			name: "Regular expression with operators in the value",
+			expr: "message~a=b",
+		},
+		{
+			name:    "Empty filter",
+			expr:    " , ",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid key",
+			expr:    "foo=bar",
+			wantErr: true,
+		},
+		{
+			name:    "Missing operator",
+			expr:    "kind",
+			wantErr: true,
+		},
+		{
+			name:    "Ordering operator not supported by the key",
+			expr:    "reason>Foo",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid severity",
+			expr:    "severity>=Bad",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid age",
+			expr:    "age>30x",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := ParseFilter(tt.expr)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func Test_FilterMatch(t *testing.T) {
+	readyFalseWarning := conditions.FalseCondition(clusterv1.ReadyCondition, "WaitingForInfrastructure", clusterv1.ConditionSeverityWarning, "waiting for 10.0.0.1")
+	readyFalseWarning.LastTransitionTime = metav1.NewTime(time.Now().Add(-1 * time.Hour))
+
+	machine := &Node{
+		Kind:       "Machine",
+		Name:       "m1",
+		Conditions: clusterv1.Conditions{*readyFalseWarning},
+	}
+	group := &Node{
+		Kind:       "Machine",
+		Name:       "zz_group",
+		GroupItems: []string{"m2", "m3"},
+		Conditions: clusterv1.Conditions{*conditions.TrueCondition(clusterv1.ReadyCondition)},
+	}
+	workers := &Node{
+		Kind:    "Workers",
+		Name:    "Workers",
+		Virtual: true,
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		node *Node
+		want bool
+	}{
+		{name: "Kind is case insensitive", expr: "kind=machine", node: machine, want: true},
+		{name: "Kind and reason", expr: "kind=Machine,reason=WaitingForInfrastructure", node: machine, want: true},
+		{name: "Kind and different reason", expr: "kind=Machine,reason=WaitingForBootstrap", node: machine, want: false},
+		{name: "Severity greater or equal", expr: "severity>=Warning", node: machine, want: true},
+		{name: "Severity greater", expr: "severity>Warning", node: machine, want: false},
+		{name: "Severity of Ready=True", expr: "severity>=Info", node: group, want: false},
+		{name: "Status not equal", expr: "status!=True", node: machine, want: true},
+		{name: "Age greater", expr: "age>30m", node: machine, want: true},
+		{name: "Age lower", expr: "age<30m", node: machine, want: false},
+		{name: "Age without ready condition", expr: "age>30m", node: workers, want: false},
+		{name: "Message regular expression", expr: "message~[0-9]+\\.[0-9]+", node: machine, want: true},
+		{name: "Name of objects in a group", expr: "name=m3", node: group, want: true},
+		{name: "Name not in a group", expr: "name!=m3", node: group, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			f, err := ParseFilter(tt.expr)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(f.Match(tt.node)).To(Equal(tt.want))
+		})
+	}
+}
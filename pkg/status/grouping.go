@@ -0,0 +1,308 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util"
+)
+
+const (
+	// GroupByStatus groups objects with the same status of the ready condition.
+	GroupByStatus = "status"
+
+	// GroupBySeverity groups objects with the same severity of the ready condition.
+	GroupBySeverity = "severity"
+
+	// GroupByReason groups objects with the same reason of the ready condition.
+	GroupByReason = "reason"
+
+	// GroupByMessage groups objects with the same message of the ready condition, or with the same part
+	// of the message matching the grouping message pattern.
+	GroupByMessage = "message"
+
+	// GroupByNormalizedMessage groups objects with the same message of the ready condition, after removing
+	// object names, IP addresses, IDs and numbers from the message.
+	GroupByNormalizedMessage = "normalized-message"
+
+	// GroupByVersion groups objects with the same Kubernetes version.
+	GroupByVersion = "version"
+
+	// GroupByFailureDomain groups objects with the same failure domain.
+	GroupByFailureDomain = "failure-domain"
+)
+
+// GroupByKeys is the list of the supported grouping keys.
+var GroupByKeys = []string{GroupByStatus, GroupBySeverity, GroupByReason, GroupByMessage, GroupByNormalizedMessage, GroupByVersion, GroupByFailureDomain}
+
+// DefaultGroupBy is the default list of grouping keys, grouping objects with the same Status, Severity and Reason
+// of the ready condition.
+var DefaultGroupBy = []string{GroupByStatus, GroupBySeverity, GroupByReason}
+
+// ParseGroupBy parses a list of comma separated grouping keys.
+func ParseGroupBy(s string) ([]string, error) {
+	var keys []string
+	for _, k := range strings.Split(s, ",") {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" {
+			continue
+		}
+		if !containsKind(GroupByKeys, k) {
+			return nil, fmt.Errorf("invalid grouping key %q, valid keys are %s", k, strings.Join(GroupByKeys, ", "))
+		}
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("at least one grouping key must be defined")
+	}
+	return keys, nil
+}
+
+// Group merges the children of the nodes with Grouping set into group nodes, in case they have the same
+// grouping key according to the tree options; nodes reporting a terminal failure, inconsistent nodes and virtual
+// nodes, e.g. failure domains, are never grouped.
+// NB. The subtrees of the nodes merged into a group are removed from the tree.
+func (od ObjectTree) Group() {
+	minSize := od.options.GroupingMinSize
+	if minSize < 2 {
+		minSize = 2
+	}
+
+	od.Walk(func(parent *Node, _ int) bool {
+		if !parent.Grouping {
+			return true
+		}
+
+		// Split the children into buckets with the same grouping key.
+		buckets := map[string][]*Node{}
+		var keys []string
+		for _, child := range od.GetChildren(parent.ID) {
+			if child.IsFailed() || child.IsInconsistent() || child.Virtual {
+				continue
+			}
+			key := od.groupingKey(child)
+			if _, ok := buckets[key]; !ok {
+				keys = append(keys, key)
+			}
+			buckets[key] = append(buckets[key], child)
+		}
+
+		// Replace the nodes in each bucket with a group node, if the bucket is big enough.
+		for _, key := range keys {
+			members := buckets[key]
+			if len(members) < minSize {
+				continue
+			}
+			// NB. The most recent events of the items, including the events of their descendants, are carried
+			// into the group, given that the subtrees are removed.
+			var events []corev1.Event
+			for _, m := range members {
+				events = append(events, od.getSubtreeEvents(m)...)
+				od.removeAll(parent, m)
+			}
+			groupNode := createGroupNode(members)
+			groupNode.Events = mostRecentEvents(events)
+			od.addInner(parent, groupNode)
+		}
+		return true
+	})
+}
+
+// groupingKey returns the key used for grouping a node, computed according to the tree options.
+func (od ObjectTree) groupingKey(node *Node) string {
+	groupBy := od.options.GroupBy
+	if len(groupBy) == 0 {
+		groupBy = DefaultGroupBy
+	}
+
+	ready := node.GetReadyCondition()
+	if ready == nil {
+		// NB. Nodes without a ready condition should not be grouped with nodes with a ready condition.
+		ready = &clusterv1.Condition{Status: "-"}
+	}
+
+	var parts []string
+	for _, k := range groupBy {
+		switch k {
+		case GroupByStatus:
+			parts = append(parts, string(ready.Status))
+		case GroupBySeverity:
+			parts = append(parts, string(ready.Severity))
+		case GroupByReason:
+			parts = append(parts, ready.Reason)
+		case GroupByMessage:
+			parts = append(parts, od.groupingMessage(ready.Message))
+		case GroupByNormalizedMessage:
+			parts = append(parts, NormalizeMessage(node.Name, ready.Message))
+		case GroupByVersion:
+			parts = append(parts, node.Version)
+		case GroupByFailureDomain:
+			parts = append(parts, node.FailureDomain)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// groupingMessage returns the part of the message to be used when grouping by message.
+func (od ObjectTree) groupingMessage(message string) string {
+	if od.options.GroupingMessagePattern == nil {
+		return message
+	}
+	match := od.options.GroupingMessagePattern.FindStringSubmatch(message)
+	switch len(match) {
+	case 0:
+		return message
+	case 1:
+		return match[0]
+	default:
+		return match[1]
+	}
+}
+
+// removeAll removes a node and all its descendants from the tree.
+func (od ObjectTree) removeAll(parent, node *Node) {
+	for _, child := range od.GetChildren(node.ID) {
+		od.removeAll(node, child)
+	}
+	od.remove(parent, node)
+}
+
+func createGroupNode(members []*Node) *Node {
+	first := members[0]
+
+	// Create a new group node with the same kind of the grouped nodes.
+	// NB. The group nodes gets a unique ID to avoid conflicts.
+	groupNode := newVirtualNode(first.Namespace, groupUID(first))
+	groupNode.Kind = first.Kind
+
+	// Update the list of items included in the group, and the fields all the items have in common.
+	groupNode.Version = first.Version
+	groupNode.FailureDomain = first.FailureDomain
+	for _, m := range members {
+		groupNode.GroupItems = append(groupNode.GroupItems, m.Name)
+		if m.Version != groupNode.Version {
+			groupNode.Version = ""
+		}
+		if m.FailureDomain != groupNode.FailureDomain {
+			groupNode.FailureDomain = ""
+		}
+	}
+	sort.Strings(groupNode.GroupItems)
+
+	// Set the group's ready condition using the worst ready condition among the items.
+	var ready *clusterv1.Condition
+	for _, m := range members {
+		mReady := m.GetReadyCondition()
+		if mReady == nil {
+			continue
+		}
+		if readyPriority(mReady) > readyPriority(ready) {
+			ready = mReady.DeepCopy()
+		}
+	}
+	groupNode.GroupStats = newGroupStats(members)
+
+	if ready != nil {
+		ready.LastTransitionTime = groupNode.GroupStats.NewestTransition
+		ready.Message = ""
+		groupNode.setCondition(ready)
+	}
+	return groupNode
+}
+
+// GroupStats provides statistics about the ready conditions of the objects in a group node.
+type GroupStats struct {
+	// OldestTransition is the oldest last transition time among the ready conditions of the objects in the group.
+	OldestTransition metav1.Time `json:"oldestTransition"`
+
+	// NewestTransition is the most recent last transition time among the ready conditions of the objects in the group.
+	NewestTransition metav1.Time `json:"newestTransition"`
+
+	// Messages are the distinct messages of the ready conditions of the objects in the group,
+	// sorted by number of occurrences.
+	Messages []GroupMessage `json:"messages,omitempty"`
+}
+
+// GroupMessage is a message of the ready condition with the number of objects in a group reporting it.
+type GroupMessage struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+func newGroupStats(members []*Node) *GroupStats {
+	stats := &GroupStats{}
+	counts := map[string]int{}
+	for _, m := range members {
+		mReady := m.GetReadyCondition()
+		if mReady == nil {
+			continue
+		}
+		t := mReady.LastTransitionTime
+		if stats.OldestTransition.IsZero() || t.Before(&stats.OldestTransition) {
+			stats.OldestTransition = t
+		}
+		if stats.NewestTransition.IsZero() || stats.NewestTransition.Before(&t) {
+			stats.NewestTransition = t
+		}
+		if mReady.Message == "" {
+			continue
+		}
+		if _, ok := counts[mReady.Message]; !ok {
+			stats.Messages = append(stats.Messages, GroupMessage{Message: mReady.Message})
+		}
+		counts[mReady.Message]++
+	}
+
+	for i := range stats.Messages {
+		stats.Messages[i].Count = counts[stats.Messages[i].Message]
+	}
+	sort.SliceStable(stats.Messages, func(i, j int) bool {
+		if stats.Messages[i].Count != stats.Messages[j].Count {
+			return stats.Messages[i].Count > stats.Messages[j].Count
+		}
+		return stats.Messages[i].Message < stats.Messages[j].Message
+	})
+	return stats
+}
+
+func groupUID(node *Node) string {
+	ready := node.GetReadyCondition()
+	if ready == nil {
+		return fmt.Sprintf("zzz_%s", util.RandomString(6))
+	}
+	return fmt.Sprintf("zz_%s_%s_%s_%s", ready.Status, ready.Severity, ready.Reason, util.RandomString(6))
+}
+
+// readyPriority returns the priority of a ready condition, with higher values for conditions
+// representing worse states.
+func readyPriority(c *clusterv1.Condition) int {
+	if c == nil {
+		return 0
+	}
+	if c.Status == corev1.ConditionTrue {
+		return 1
+	}
+	switch c.Severity {
+	case clusterv1.ConditionSeverityError:
+		return 5
+	case clusterv1.ConditionSeverityWarning:
+		return 4
+	case clusterv1.ConditionSeverityInfo:
+		return 3
+	default:
+		return 2
+	}
+}
+
+func containsKind(list []string, kind string) bool {
+	for _, k := range list {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package status
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeGroupOptions(t *testing.T) {
+	var machines []*clusterv1.Machine
+	for _, m := range []struct {
+		name    string
+		version string
+		ready   *clusterv1.Condition
+	}{
+		{name: "m1", version: "v1.18.2", ready: conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityInfo, "failed for host-1: timeout")},
+		{name: "m2", version: "v1.18.2", ready: conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, "failed for host-2: timeout")},
+		{name: "m3", version: "v1.19.1", ready: conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, "failed for host-3: quota")},
+		{name: "m4", version: "v1.19.1", ready: conditions.TrueCondition(clusterv1.ReadyCondition)},
+	} {
+		version := m.version
+		machine := testMachine(m.name, m.ready)
+		machine.Spec.Version = &version
+		machines = append(machines, machine)
+	}
+
+	tests := []struct {
+		name    string
+		options ObjectTreeOptions
+		want    []string
+	}{
+		{
+			name:    "Default grouping",
+			options: ObjectTreeOptions{},
+			want:    []string{"m1", "m2,m3", "m4"},
+		},
+		{
+			name:    "Group by reason",
+			options: ObjectTreeOptions{GroupBy: []string{GroupByReason}},
+			want:    []string{"m1,m2,m3", "m4"},
+		},
+		{
+			name:    "Group by version",
+			options: ObjectTreeOptions{GroupBy: []string{GroupByVersion}},
+			want:    []string{"m1,m2", "m3,m4"},
+		},
+		{
+			name:    "Group by message pattern",
+			options: ObjectTreeOptions{GroupBy: []string{GroupByMessage}, GroupingMessagePattern: regexp.MustCompile(`: (\w+)$`)},
+			want:    []string{"m1,m2", "m3", "m4"},
+		},
+		{
+			name:    "Group by normalized message",
+			options: ObjectTreeOptions{GroupBy: []string{GroupByNormalizedMessage}},
+			want:    []string{"m1,m2", "m3", "m4"},
+		},
+		{
+			name:    "Group with min size",
+			options: ObjectTreeOptions{GroupBy: []string{GroupByReason}, GroupingMinSize: 4},
+			want:    []string{"m1", "m2", "m3", "m4"},
+		},
+		{
+			name:    "Grouping parents not matching",
+			options: ObjectTreeOptions{GroupingParents: []string{"MachineDeployment"}},
+			want:    []string{"m1", "m2", "m3", "m4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := NewObjectTree(testCluster(), tt.options)
+			controlPlane := objs.AddVirtual(objs.GetRoot(), "ControlPlane", GroupingObject(true))
+			for _, m := range machines {
+				objs.Add(controlPlane, m)
+			}
+			objs.Group()
+
+			var got []string
+			for _, child := range objs.GetChildren(controlPlane.ID) {
+				if child.IsGroup() {
+					got = append(got, strings.Join(child.GroupItems, ","))
+					continue
+				}
+				got = append(got, child.Name)
+			}
+			sort.Strings(got)
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_newGroupStats(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	node := func(message string, d time.Duration) *Node {
+		ready := conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, message)
+		ready.LastTransitionTime = metav1.NewTime(now.Add(-d))
+		return &Node{Conditions: clusterv1.Conditions{*ready}}
+	}
+
+	stats := newGroupStats([]*Node{
+		node("quota exceeded", time.Minute),
+		node("timeout", 2*time.Hour),
+		node("quota exceeded", time.Hour),
+		node("", 30*time.Minute),
+		{},
+	})
+	g.Expect(stats.OldestTransition.Time).To(BeTemporally("==", now.Add(-2*time.Hour)))
+	g.Expect(stats.NewestTransition.Time).To(BeTemporally("==", now.Add(-time.Minute)))
+	g.Expect(stats.Messages).To(Equal([]GroupMessage{
+		{Message: "quota exceeded", Count: 2},
+		{Message: "timeout", Count: 1},
+	}))
+}
+
+func Test_ObjectTreeGroupRootAsGroupingParent(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ObjectTreeOptions
+		want    []string
+	}{
+		{
+			name:    "Root not in the grouping parents",
+			options: ObjectTreeOptions{},
+			want:    []string{"m1", "m2"},
+		},
+		{
+			name:    "Root in the grouping parents",
+			options: ObjectTreeOptions{GroupingParents: []string{"Cluster"}},
+			want:    []string{"m1,m2"},
+		},
+		{
+			name:    "Root in the grouping parents with grouping disabled",
+			options: ObjectTreeOptions{GroupingParents: []string{"Cluster"}, DisableGroupObjects: true},
+			want:    []string{"m1", "m2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := NewObjectTree(testCluster(), tt.options)
+			for _, name := range []string{"m1", "m2"} {
+				objs.Add(objs.GetRoot(), testMachine(name, conditions.TrueCondition(clusterv1.ReadyCondition)))
+			}
+			objs.Group()
+
+			var got []string
+			for _, child := range objs.GetChildren(objs.GetRoot().ID) {
+				if child.IsGroup() {
+					got = append(got, strings.Join(child.GroupItems, ","))
+					continue
+				}
+				got = append(got, child.Name)
+			}
+			sort.Strings(got)
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package status
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// testCluster returns a Cluster to be used as the root of the object trees in tests.
+func testCluster() *clusterv1.Cluster {
+	return &clusterv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "Cluster",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      "cluster",
+			UID:       "cluster",
+		},
+	}
+}
+
+// testMachine returns a Machine with the given name, also used as UID, and the given ready condition, if any.
+func testMachine(name string, ready *clusterv1.Condition) *clusterv1.Machine {
+	m := &clusterv1.Machine{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "Machine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			UID:       types.UID(name),
+		},
+	}
+	if ready != nil {
+		conditions.Set(m, ready)
+	}
+	return m
+}
+
+// testMachineDeployment returns a MachineDeployment with the given name, also used as UID.
+func testMachineDeployment(name string) *clusterv1.MachineDeployment {
+	return &clusterv1.MachineDeployment{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "MachineDeployment",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "ns",
+			Name:      name,
+			UID:       types.UID(name),
+		},
+	}
+}
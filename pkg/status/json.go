@@ -0,0 +1,76 @@
+package status
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+// TreeNode is the serializable representation of a node in the object tree and of its descendants,
+// e.g. for returning the object tree as JSON.
+type TreeNode struct {
+	Kind               string                    `json:"kind"`
+	Namespace          string                    `json:"namespace,omitempty"`
+	Name               string                    `json:"name"`
+	MetaName           string                    `json:"metaName,omitempty"`
+	Virtual            bool                      `json:"virtual,omitempty"`
+	GroupItems         []string                  `json:"groupItems,omitempty"`
+	GroupStats         *GroupStats               `json:"groupStats,omitempty"`
+	Conditions         clusterv1.Conditions      `json:"conditions,omitempty"`
+	StuckConditions    []clusterv1.ConditionType `json:"stuckConditions,omitempty"`
+	Inconsistencies    []string                  `json:"inconsistencies,omitempty"`
+	Version            string                    `json:"version,omitempty"`
+	FailureDomain      string                    `json:"failureDomain,omitempty"`
+	FailureDomainStats *FailureDomainStats       `json:"failureDomainStats,omitempty"`
+	VersionRollout     *VersionRollout           `json:"versionRollout,omitempty"`
+	Failure            *Failure                  `json:"failure,omitempty"`
+	MissingReferences  []MissingReference        `json:"missingReferences,omitempty"`
+	Deleted            bool                      `json:"deleted,omitempty"`
+	Paused             bool                      `json:"paused,omitempty"`
+	Stale              bool                      `json:"stale,omitempty"`
+	Events             []corev1.Event            `json:"events,omitempty"`
+	Children           []*TreeNode               `json:"children,omitempty"`
+}
+
+// ToTreeNode returns the serializable representation of the object tree; children are sorted
+// in the same order used by Walk.
+func (od ObjectTree) ToTreeNode() *TreeNode {
+	var root *TreeNode
+	var ancestors []*TreeNode
+	od.Walk(func(node *Node, depth int) bool {
+		t := newTreeNode(node)
+		ancestors = append(ancestors[:depth], t)
+		if depth == 0 {
+			root = t
+			return true
+		}
+		parent := ancestors[depth-1]
+		parent.Children = append(parent.Children, t)
+		return true
+	})
+	return root
+}
+
+func newTreeNode(node *Node) *TreeNode {
+	return &TreeNode{
+		Kind:               node.Kind,
+		Namespace:          node.Namespace,
+		Name:               node.Name,
+		MetaName:           node.MetaName,
+		Virtual:            node.Virtual,
+		GroupItems:         node.GroupItems,
+		GroupStats:         node.GroupStats,
+		Conditions:         node.Conditions,
+		StuckConditions:    node.StuckConditions,
+		Inconsistencies:    node.Inconsistencies,
+		Version:            node.Version,
+		FailureDomain:      node.FailureDomain,
+		FailureDomainStats: node.FailureDomainStats,
+		VersionRollout:     node.VersionRollout,
+		Failure:            node.Failure,
+		MissingReferences:  node.MissingReferences,
+		Deleted:            node.IsDeleted(),
+		Paused:             node.Paused,
+		Stale:              node.Stale,
+		Events:             node.Events,
+	}
+}
@@ -0,0 +1,25 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_ObjectTreeToTreeNode(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	workers := objs.AddVirtual(objs.GetRoot(), "Workers")
+	objs.Add(workers, testMachine("m2", nil))
+	objs.Add(workers, testMachine("m1", nil))
+
+	root := objs.ToTreeNode()
+	g.Expect(root.Name).To(Equal("cluster"))
+	g.Expect(root.Children).To(HaveLen(1))
+	g.Expect(root.Children[0].Name).To(Equal("Workers"))
+	g.Expect(root.Children[0].Virtual).To(BeTrue())
+	g.Expect(root.Children[0].Children).To(HaveLen(2))
+	g.Expect(root.Children[0].Children[0].Name).To(Equal("m1"))
+	g.Expect(root.Children[0].Children[1].Name).To(Equal("m2"))
+}
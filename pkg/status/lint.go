@@ -0,0 +1,112 @@
+package status
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+const (
+	// LintMissingReady reports objects without a ready condition.
+	LintMissingReady = "missing-ready"
+
+	// LintFalseWithoutSeverity reports conditions with status False and without a severity.
+	LintFalseWithoutSeverity = "false-without-severity"
+
+	// LintReasonNotCamelCase reports conditions with a reason not in CamelCase, ignoring the source reference added
+	// by Cluster API when aggregating conditions.
+	LintReasonNotCamelCase = "reason-not-camel-case"
+
+	// LintReadyTrueWithFalseConditions reports objects with the ready condition true while other conditions are False.
+	LintReadyTrueWithFalseConditions = "ready-true-with-false-conditions"
+
+	// LintMessageOnTrue reports conditions with status True and a message.
+	LintMessageOnTrue = "message-on-true"
+
+	// LintZeroLastTransitionTime reports conditions without a last transition time.
+	LintZeroLastTransitionTime = "zero-last-transition-time"
+)
+
+// LintRules is the list of the supported lint rules.
+var LintRules = []string{LintMissingReady, LintFalseWithoutSeverity, LintReasonNotCamelCase, LintReadyTrueWithFalseConditions, LintMessageOnTrue, LintZeroLastTransitionTime}
+
+var camelCaseRegex = regexp.MustCompile(`^[A-Z][a-zA-Z0-9]*$`)
+
+// LintViolation is a violation of the Cluster API condition conventions.
+type LintViolation struct {
+	// Node violating the convention.
+	Node *Node
+
+	// Condition violating the convention; it is empty for violations related to the object, e.g. missing ready condition.
+	Condition clusterv1.ConditionType
+
+	// Rule violated.
+	Rule string
+
+	// Message describing the violation.
+	Message string
+}
+
+// Lint returns the violations of the Cluster API condition conventions for all the objects in the tree.
+// NB. Virtual nodes and group nodes are not linted, because their conditions are computed by the object tree.
+func (od ObjectTree) Lint() []LintViolation {
+	var violations []LintViolation
+	od.Walk(func(node *Node, _ int) bool {
+		if node.Virtual {
+			return true
+		}
+		violations = append(violations, lintNode(node)...)
+		return true
+	})
+	return violations
+}
+
+func lintNode(node *Node) []LintViolation {
+	var violations []LintViolation
+	add := func(c clusterv1.ConditionType, rule, format string, a ...interface{}) {
+		violations = append(violations, LintViolation{Node: node, Condition: c, Rule: rule, Message: fmt.Sprintf(format, a...)})
+	}
+
+	// NB. Objects without conditions in their API, e.g. MachineDeployments in Cluster API v1alpha3, cannot be fixed,
+	// so they are not reported as missing the ready condition.
+	ready := node.GetReadyCondition()
+	if ready == nil && (node.Object == nil || hasConditions(node.Object)) {
+		add("", LintMissingReady, "the object does not have a %s condition", clusterv1.ReadyCondition)
+	}
+
+	// NB. The severity is checked on the conditions stored in the object, because when reading unstructured objects
+	// the severity of the conditions not True is inferred if missing.
+	severities := map[clusterv1.ConditionType]clusterv1.ConditionSeverity{}
+	for _, c := range node.storedConditions() {
+		severities[c.Type] = c.Severity
+	}
+
+	var falseConditions []string
+	for i := range node.Conditions {
+		c := &node.Conditions[i]
+		if c.Status == corev1.ConditionFalse && severities[c.Type] == clusterv1.ConditionSeverityNone {
+			add(c.Type, LintFalseWithoutSeverity, "the condition is False but it does not have a severity")
+		}
+		// NB. Reasons with a source reference, e.g. WaitingForInfrastructure@Machine/m1, are checked without the reference.
+		if reason := trimSourceRef(c.Reason); reason != "" && !camelCaseRegex.MatchString(reason) {
+			add(c.Type, LintReasonNotCamelCase, "the reason %q is not CamelCase", c.Reason)
+		}
+		if c.Status == corev1.ConditionTrue && c.Message != "" {
+			add(c.Type, LintMessageOnTrue, "the condition is True but it has the message %q", c.Message)
+		}
+		if c.LastTransitionTime.IsZero() {
+			add(c.Type, LintZeroLastTransitionTime, "the condition does not have a last transition time")
+		}
+		if c.Type != clusterv1.ReadyCondition && c.Status == corev1.ConditionFalse {
+			falseConditions = append(falseConditions, string(c.Type))
+		}
+	}
+
+	if ready != nil && ready.Status == corev1.ConditionTrue && len(falseConditions) > 0 {
+		add(clusterv1.ReadyCondition, LintReadyTrueWithFalseConditions, "the condition is True while %s are False", strings.Join(falseConditions, ", "))
+	}
+	return violations
+}
@@ -0,0 +1,164 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_lintNode(t *testing.T) {
+	withTime := func(c *clusterv1.Condition) *clusterv1.Condition {
+		c.LastTransitionTime = metav1.Now()
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		conditions []*clusterv1.Condition
+		want       []string
+	}{
+		{
+			name:       "Valid conditions",
+			conditions: []*clusterv1.Condition{withTime(conditions.TrueCondition(clusterv1.ReadyCondition))},
+			want:       nil,
+		},
+		{
+			name:       "Missing ready",
+			conditions: []*clusterv1.Condition{withTime(conditions.TrueCondition(clusterv1.InfrastructureReadyCondition))},
+			want:       []string{LintMissingReady},
+		},
+		{
+			name: "False without severity and reason not CamelCase",
+			conditions: []*clusterv1.Condition{
+				withTime(conditions.FalseCondition(clusterv1.ReadyCondition, "waiting for infra", clusterv1.ConditionSeverityNone, "")),
+			},
+			want: []string{LintFalseWithoutSeverity, LintReasonNotCamelCase},
+		},
+		{
+			name: "Aggregated reason with source reference",
+			conditions: []*clusterv1.Condition{
+				withTime(conditions.FalseCondition(clusterv1.ReadyCondition, "WaitingForInfrastructure@Machine/m1", clusterv1.ConditionSeverityInfo, "")),
+			},
+			want: nil,
+		},
+		{
+			name: "Ready true with false conditions",
+			conditions: []*clusterv1.Condition{
+				withTime(conditions.TrueCondition(clusterv1.ReadyCondition)),
+				withTime(conditions.FalseCondition(clusterv1.InfrastructureReadyCondition, "Foo", clusterv1.ConditionSeverityInfo, "")),
+			},
+			want: []string{LintReadyTrueWithFalseConditions},
+		},
+		{
+			name: "Message on true",
+			conditions: []*clusterv1.Condition{
+				withTime(&clusterv1.Condition{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue, Message: "all good"}),
+			},
+			want: []string{LintMessageOnTrue},
+		},
+		{
+			name: "Zero last transition time",
+			conditions: []*clusterv1.Condition{
+				{Type: clusterv1.ReadyCondition, Status: corev1.ConditionTrue},
+			},
+			want: []string{LintZeroLastTransitionTime},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			node := &Node{Kind: "Machine", Name: "m1"}
+			for _, c := range tt.conditions {
+				node.setCondition(c)
+			}
+
+			var got []string
+			for _, v := range lintNode(node) {
+				got = append(got, v.Rule)
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_lintNodeUnstructuredFalseWithoutSeverity(t *testing.T) {
+	g := NewWithT(t)
+
+	infra := &unstructured.Unstructured{}
+	infra.SetKind("InfrastructureMachine")
+	infra.SetNamespace("ns")
+	infra.SetName("m1")
+	infra.SetUID("m1-infra")
+	ready := conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityNone, "")
+	ready.LastTransitionTime = metav1.Now()
+	conditions.UnstructuredSetter(infra).SetConditions(clusterv1.Conditions{*ready})
+
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	node := objs.Add(objs.GetRoot(), infra)
+
+	// NB. The severity is inferred when reading the conditions, but the violation is still reported.
+	g.Expect(node.GetReadyCondition().Severity).To(Equal(clusterv1.ConditionSeverityWarning))
+	var got []string
+	for _, v := range lintNode(node) {
+		got = append(got, v.Rule)
+	}
+	g.Expect(got).To(Equal([]string{LintFalseWithoutSeverity}))
+}
+
+func Test_lintNodeMissingReady(t *testing.T) {
+	withoutConditions := &unstructured.Unstructured{}
+	withoutConditions.SetKind("FooMachine")
+	withoutConditions.SetName("m1")
+	withoutConditions.SetUID("m1-foo")
+
+	withConditions := withoutConditions.DeepCopy()
+	withConditions.Object["status"] = map[string]interface{}{"conditions": []interface{}{}}
+
+	tests := []struct {
+		name string
+		obj  controllerutil.Object
+		want []string
+	}{
+		{
+			name: "Typed object with conditions",
+			obj:  testMachine("m1", nil),
+			want: []string{LintMissingReady},
+		},
+		{
+			name: "Typed object without conditions in its API",
+			obj:  testMachineDeployment("md"),
+			want: nil,
+		},
+		{
+			name: "Unstructured object with conditions",
+			obj:  withConditions,
+			want: []string{LintMissingReady},
+		},
+		{
+			name: "Unstructured object without conditions",
+			obj:  withoutConditions,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+			node := objs.Add(objs.GetRoot(), tt.obj)
+
+			var got []string
+			for _, v := range lintNode(node) {
+				got = append(got, v.Rule)
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
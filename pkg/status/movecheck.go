@@ -0,0 +1,216 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MoveNotReady reports objects with a ready condition not true.
+	MoveNotReady = "NotReady"
+
+	// MoveFailed reports objects with a terminal failure.
+	MoveFailed = "Failed"
+
+	// MoveDeleting reports objects being deleted.
+	MoveDeleting = "Deleting"
+
+	// MoveProvisioning reports objects being provisioned or scaled.
+	MoveProvisioning = "Provisioning"
+
+	// MoveMissingReference reports objects referencing objects that cannot be read.
+	MoveMissingReference = "MissingReference"
+
+	// MoveLeftBehind reports objects belonging to the cluster which are not part of the object tree,
+	// and so they would not be moved together with the cluster.
+	MoveLeftBehind = "LeftBehind"
+)
+
+// inFlightPhases are the phases documenting that an object is being provisioned or scaled.
+var inFlightPhases = map[string]bool{
+	"Pending":      true,
+	"Provisioning": true,
+	"ScalingUp":    true,
+	"ScalingDown":  true,
+}
+
+// MoveIssue is a reason preventing a cluster to be safely moved to another management cluster.
+type MoveIssue struct {
+	// Node with the issue; it is nil for objects which are not part of the object tree, e.g. objects left behind.
+	Node *Node
+
+	// Object with the issue, in the Kind/name form.
+	Object string
+
+	// Reason of the issue, e.g. NotReady.
+	Reason string
+
+	// Message describing the issue.
+	Message string
+}
+
+// CheckMove returns the issues preventing the objects in the tree to be safely moved to another management cluster;
+// every object must be ready, nothing must be deleting or provisioning, and all the referenced objects must exist.
+// NB. CheckMove checks also the children hidden because of the NoEcho option, but it does not check the objects
+// included in group nodes, so it should be called on a tree discovered with grouping disabled.
+func (od ObjectTree) CheckMove() []MoveIssue {
+	var issues []MoveIssue
+	od.Walk(func(node *Node, _ int) bool {
+		issues = append(issues, checkMoveNode(node)...)
+		for _, hidden := range node.HiddenChildren {
+			issues = append(issues, checkMoveNode(hidden)...)
+		}
+		return true
+	})
+	return issues
+}
+
+func checkMoveNode(node *Node) []MoveIssue {
+	if node.Virtual {
+		return nil
+	}
+
+	var issues []MoveIssue
+	add := func(reason, format string, a ...interface{}) {
+		issues = append(issues, MoveIssue{Node: node, Object: fmt.Sprintf("%s/%s", node.Kind, node.Name), Reason: reason, Message: fmt.Sprintf(format, a...)})
+	}
+
+	if node.IsFailed() {
+		add(MoveFailed, "the object reports the failure %s", node.Failure.Reason)
+	}
+	if ready := node.GetReadyCondition(); ready != nil && ready.Status != corev1.ConditionTrue {
+		add(MoveNotReady, "the %s condition is %s: %s", clusterv1.ReadyCondition, ready.Status, ready.Reason)
+	}
+	if node.IsDeleted() {
+		add(MoveDeleting, "the object is being deleted")
+	}
+	if node.Object != nil {
+		phase := GetPhase(node.Object)
+		if inFlightPhases[phase] || (node.Kind == "Machine" && phase == string(clusterv1.MachinePhaseProvisioned)) {
+			add(MoveProvisioning, "the object is in the %s phase", phase)
+		}
+		if r := GetReplicas(node.Object); r != nil && r.Ready != r.Desired {
+			add(MoveProvisioning, "%d of %d replicas ready", r.Ready, r.Desired)
+		}
+	}
+	for _, ref := range node.MissingReferences {
+		add(MoveMissingReference, "%s %s/%s cannot be read: %s", ref.Field, ref.Kind, ref.Name, ref.Error)
+	}
+	return issues
+}
+
+// CheckLeftBehind returns an issue for each object labeled as belonging to the cluster which is not part of
+// the object tree, nor owned by an object in the object tree, and so it would be left behind when moving the cluster.
+// The candidate objects are the Cluster API objects and the objects with the same kinds of the objects in the tree.
+// NB. CheckLeftBehind should be called on a tree discovered with grouping disabled.
+func CheckLeftBehind(ctx context.Context, c client.Client, objs *ObjectTree) ([]MoveIssue, error) {
+	root := objs.GetRoot()
+
+	type candidateList struct {
+		kind string
+		list runtime.Object
+	}
+	lists := []candidateList{
+		{kind: "MachineDeployment", list: &clusterv1.MachineDeploymentList{}},
+		{kind: "MachineSet", list: &clusterv1.MachineSetList{}},
+		{kind: "Machine", list: &clusterv1.MachineList{}},
+		{kind: "MachineHealthCheck", list: &clusterv1.MachineHealthCheckList{}},
+	}
+
+	known := map[types.UID]bool{}
+	kinds := map[string]bool{}
+	var collect func(node *Node)
+	collect = func(node *Node) {
+		known[node.ID] = true
+		if u, ok := node.Object.(*unstructured.Unstructured); ok && !kinds[u.GroupVersionKind().String()] {
+			gvk := u.GroupVersionKind()
+			kinds[gvk.String()] = true
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			lists = append(lists, candidateList{kind: gvk.Kind, list: list})
+		}
+		for _, hidden := range node.HiddenChildren {
+			collect(hidden)
+		}
+	}
+	objs.Walk(func(node *Node, _ int) bool {
+		collect(node)
+		return true
+	})
+
+	type candidate struct {
+		name   string
+		uid    types.UID
+		owners []types.UID
+	}
+	var candidates []candidate
+	for _, l := range lists {
+		if err := c.List(ctx, l.list, client.InNamespace(root.Namespace), client.MatchingLabels{clusterv1.ClusterLabelName: root.Name}); err != nil {
+			return nil, fmt.Errorf("failed to list %s objects: %v", l.kind, err)
+		}
+		items, err := meta.ExtractList(l.list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract %s objects: %v", l.kind, err)
+		}
+		for _, item := range items {
+			o, err := meta.Accessor(item)
+			if err != nil {
+				return nil, fmt.Errorf("failed to access %s object: %v", l.kind, err)
+			}
+			if known[o.GetUID()] {
+				continue
+			}
+			cand := candidate{
+				name: fmt.Sprintf("%s/%s", l.kind, o.GetName()),
+				uid:  o.GetUID(),
+			}
+			for _, ref := range o.GetOwnerReferences() {
+				cand.owners = append(cand.owners, ref.UID)
+			}
+			candidates = append(candidates, cand)
+		}
+	}
+
+	// NB. Objects owned by a known object are moved together with their owner, e.g. MachineSets owned by
+	// a MachineDeployment, so ownership is propagated until no more objects become known.
+	for changed := true; changed; {
+		changed = false
+		for _, cand := range candidates {
+			if known[cand.uid] {
+				continue
+			}
+			for _, owner := range cand.owners {
+				if known[owner] {
+					known[cand.uid] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var issues []MoveIssue
+	for _, cand := range candidates {
+		if known[cand.uid] {
+			continue
+		}
+		issues = append(issues, MoveIssue{
+			Object:  cand.name,
+			Reason:  MoveLeftBehind,
+			Message: "the object belongs to the cluster but it is not owned by any object in the cluster",
+		})
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Object < issues[j].Object
+	})
+	return issues, nil
+}
@@ -0,0 +1,78 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeCheckMove(t *testing.T) {
+	now := metav1.Now()
+
+	tests := []struct {
+		name             string
+		machine          func(m *clusterv1.Machine)
+		missingReference bool
+		wantReasons      []string
+	}{
+		{
+			name:        "Ready and running machine",
+			machine:     func(m *clusterv1.Machine) {},
+			wantReasons: nil,
+		},
+		{
+			name: "Machine not ready",
+			machine: func(m *clusterv1.Machine) {
+				conditions.MarkFalse(m, clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityWarning, "")
+			},
+			wantReasons: []string{MoveNotReady},
+		},
+		{
+			name: "Machine provisioning",
+			machine: func(m *clusterv1.Machine) {
+				m.Status.Phase = string(clusterv1.MachinePhaseProvisioned)
+			},
+			wantReasons: []string{MoveProvisioning},
+		},
+		{
+			name: "Machine deleting",
+			machine: func(m *clusterv1.Machine) {
+				m.DeletionTimestamp = &now
+			},
+			wantReasons: []string{MoveDeleting},
+		},
+		{
+			name:             "Machine with a missing reference",
+			machine:          func(m *clusterv1.Machine) {},
+			missingReference: true,
+			wantReasons:      []string{MoveMissingReference},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := testCluster()
+			conditions.MarkTrue(cluster, clusterv1.ReadyCondition)
+			machine := testMachine("m1", conditions.TrueCondition(clusterv1.ReadyCondition))
+			machine.Status.Phase = string(clusterv1.MachinePhaseRunning)
+			tt.machine(machine)
+
+			objs := NewObjectTree(cluster, ObjectTreeOptions{})
+			machineNode := objs.Add(objs.GetRoot(), machine)
+			if tt.missingReference {
+				machineNode.MissingReferences = []MissingReference{{Field: "spec.infrastructureRef", Kind: "InfrastructureMachine", Name: "m1", Error: "not found"}}
+			}
+
+			var reasons []string
+			for _, issue := range objs.CheckMove() {
+				g.Expect(issue.Object).To(Equal("Machine/m1"))
+				reasons = append(reasons, issue.Reason)
+			}
+			g.Expect(reasons).To(Equal(tt.wantReasons))
+		})
+	}
+}
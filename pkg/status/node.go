@@ -0,0 +1,239 @@
+package status
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Node is an item in the object tree; it carries the information required by the presentation layer
+// without changing the object it was created from.
+type Node struct {
+	// ID uniquely identifies the node in the object tree.
+	ID types.UID
+
+	// Kind of the node, e.g. Machine; for group nodes this is the kind of the objects in the group.
+	Kind string
+
+	// Namespace of the node.
+	Namespace string
+
+	// Name of the node.
+	Name string
+
+	// Object is the object the node was created from; it is nil for virtual nodes.
+	Object controllerutil.Object
+
+	// MetaName is the name that should be used for the node in the presentation layer,
+	// e.g. ControlPlane for KCP.
+	MetaName string
+
+	// Virtual documents that the node does not correspond to any physical object, but instead it is
+	// introduced to provide a better representation of the cluster status, e.g. workers.
+	Virtual bool
+
+	// Grouping documents that the children of the node will be grouped in case the ready condition
+	// has the same Status, Severity and Reason.
+	Grouping bool
+
+	// GroupItems contains the names of the objects included in a group node; it is empty for all the other nodes.
+	GroupItems []string
+
+	// GroupStats provides statistics about the objects included in a group node; it is nil for all the other nodes.
+	GroupStats *GroupStats
+
+	// ShowConditions documents that the presentation layer should show all the conditions for the node.
+	ShowConditions bool
+
+	// Conditions of the node; for nodes created from an object, this is a copy of the object's conditions.
+	Conditions clusterv1.Conditions
+
+	// Version is the Kubernetes version of the node, if any.
+	Version string
+
+	// FailureDomain is the failure domain of the node, if any.
+	FailureDomain string
+
+	// FailureDomainStats provides the readiness counts for the machines in a failure domain; it is nil for all the
+	// nodes except the virtual nodes organizing machines by failure domain.
+	FailureDomainStats *FailureDomainStats
+
+	// VersionRollout is the progress of the version rollout for control planes and MachineDeployments, if any.
+	VersionRollout *VersionRollout
+
+	// Failure is the terminal failure reported by the object, if any.
+	Failure *Failure
+
+	// MissingReferences are the references of the object the node was created from that cannot be read, if any.
+	MissingReferences []MissingReference
+
+	// Paused documents that the reconciliation of the object the node was created from is paused.
+	// NB. Reconciliation is paused also for all the objects of a paused cluster, see ObjectTree.IsPaused.
+	Paused bool
+
+	// Stale documents that the status of the object the node was created from was not reconciled for the
+	// latest generation of the object, and so the node's conditions could be misleading.
+	Stale bool
+
+	// HiddenChildren are the children of the node hidden because of the NoEcho option, if any; they are not part
+	// of the object tree, but they are kept for analysing the consistency of the node's conditions.
+	HiddenChildren []*Node
+
+	// Inconsistencies describe the mismatches between the ready condition of the node and the ready condition
+	// of its children, if any; they usually point to a stale or buggy controller.
+	Inconsistencies []string
+
+	// StuckConditions are the conditions of the node which are False or Unknown for longer than the stuck threshold, if any.
+	StuckConditions []clusterv1.ConditionType
+
+	// Events are the most recent warning events for the object, if any; they include the events for the children
+	// hidden because of the NoEcho option and, for group nodes, the events for the objects in the group.
+	Events []corev1.Event
+}
+
+// MissingReference is a reference to an object that cannot be read, e.g. because it does not exist.
+type MissingReference struct {
+	// Field of the referencing object containing the reference, e.g. spec.infrastructureRef.
+	Field string `json:"field"`
+
+	// Kind of the referenced object.
+	Kind string `json:"kind"`
+
+	// Name of the referenced object.
+	Name string `json:"name"`
+
+	// Error returned when reading the referenced object.
+	Error string `json:"error"`
+}
+
+func newNode(obj controllerutil.Object) *Node {
+	return &Node{
+		ID:            obj.GetUID(),
+		Kind:          obj.GetObjectKind().GroupVersionKind().Kind,
+		Namespace:     obj.GetNamespace(),
+		Name:          obj.GetName(),
+		Object:        obj,
+		Conditions:    getConditions(obj),
+		Version:       GetVersion(obj),
+		FailureDomain: GetFailureDomain(obj),
+		Failure:       GetFailure(obj),
+		Paused:        IsPaused(obj),
+		Stale:         IsStale(obj),
+	}
+}
+
+func newVirtualNode(namespace, name string) *Node {
+	return &Node{
+		ID:        types.UID(fmt.Sprintf("%s, %s/%s", "", namespace, name)),
+		Kind:      name,
+		Namespace: namespace,
+		Name:      name,
+		Virtual:   true,
+	}
+}
+
+// IsGroup returns true if the node is the grouping of sibling nodes, e.g. a group of machines.
+func (n *Node) IsGroup() bool {
+	return len(n.GroupItems) > 0
+}
+
+// IsFailed returns true if the node reports a terminal failure.
+func (n *Node) IsFailed() bool {
+	return n.Failure != nil
+}
+
+// IsHealthy returns true if the node does not report a terminal failure and its ready condition is true.
+// NB. Nodes without a ready condition, e.g. virtual nodes, are considered healthy.
+func (n *Node) IsHealthy() bool {
+	if n.IsFailed() {
+		return false
+	}
+	ready := n.GetReadyCondition()
+	return ready == nil || ready.Status == corev1.ConditionTrue
+}
+
+// IsStuck returns true if the ready condition of the node is False or Unknown for longer than the stuck threshold.
+func (n *Node) IsStuck() bool {
+	return n.IsConditionStuck(clusterv1.ReadyCondition)
+}
+
+// IsConditionStuck returns true if the condition with the given type is False or Unknown for longer than the stuck threshold.
+func (n *Node) IsConditionStuck(t clusterv1.ConditionType) bool {
+	for _, s := range n.StuckConditions {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInconsistent returns true if the ready condition of the node does not match the ready condition of its children.
+func (n *Node) IsInconsistent() bool {
+	return len(n.Inconsistencies) > 0
+}
+
+// IsDeleted returns true if the object the node was created from is being deleted.
+func (n *Node) IsDeleted() bool {
+	return n.Object != nil && !n.Object.GetDeletionTimestamp().IsZero()
+}
+
+// GetCondition returns the condition with the given type, if any.
+func (n *Node) GetCondition(t clusterv1.ConditionType) *clusterv1.Condition {
+	for i := range n.Conditions {
+		if n.Conditions[i].Type == t {
+			return &n.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// GetReadyCondition returns the ready condition for the node, if any.
+func (n *Node) GetReadyCondition() *clusterv1.Condition {
+	return n.GetCondition(clusterv1.ReadyCondition)
+}
+
+// storedConditions returns the conditions of the node as stored in the object, that is without the severity inferred
+// when reading unstructured objects; for virtual nodes, the conditions of the node are returned.
+func (n *Node) storedConditions() clusterv1.Conditions {
+	if n.Object == nil {
+		return n.Conditions
+	}
+	return getStoredConditions(n.Object)
+}
+
+// storedReadyCondition returns the ready condition of the node as stored in the object, if any.
+func (n *Node) storedReadyCondition() *clusterv1.Condition {
+	for _, c := range n.storedConditions() {
+		if c.Type == clusterv1.ReadyCondition {
+			return c.DeepCopy()
+		}
+	}
+	return nil
+}
+
+// GetOtherConditions returns all the conditions for the node except the ready condition, sorted by type.
+func (n *Node) GetOtherConditions() []*clusterv1.Condition {
+	var conditions []*clusterv1.Condition
+	for i := range n.Conditions {
+		if n.Conditions[i].Type != clusterv1.ReadyCondition {
+			conditions = append(conditions, &n.Conditions[i])
+		}
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i].Type < conditions[j].Type
+	})
+	return conditions
+}
+
+// setCondition sets a condition on the node, replacing the existing condition with the same type, if any.
+func (n *Node) setCondition(c *clusterv1.Condition) {
+	if existing := n.GetCondition(c.Type); existing != nil {
+		*existing = *c
+		return
+	}
+	n.Conditions = append(n.Conditions, *c)
+}
@@ -0,0 +1,41 @@
+package status
+
+import (
+	"regexp"
+	"strings"
+)
+
+// messageNormalizers are the rules used for normalizing messages, applied in order; each rule replaces
+// the parts of the message which are specific of an object, e.g. IP addresses or IDs, with a placeholder.
+var messageNormalizers = []struct {
+	re          *regexp.Regexp
+	placeholder string
+}{
+	// UUIDs, e.g. 6ba7b810-9dad-11d1-80b4-00c04fd430c8.
+	{re: regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), placeholder: "<id>"},
+	// IPv4 addresses, with an optional port, e.g. 10.0.0.1:6443.
+	{re: regexp.MustCompile(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`), placeholder: "<ip>"},
+	// IPv6 addresses, e.g. fd00:10:96::1.
+	{re: regexp.MustCompile(`\b[0-9a-fA-F]{1,4}(::?[0-9a-fA-F]{1,4}){2,7}\b`), placeholder: "<ip>"},
+	// Cloud provider IDs, e.g. i-0123456789abcdef0.
+	{re: regexp.MustCompile(`\b[a-z]+-[0-9a-f]{8,}\b`), placeholder: "<id>"},
+	// Names generated by Kubernetes, e.g. md-0-6d9c8f7b5-x2kqz; the random suffix never contains vowels.
+	{re: regexp.MustCompile(`\b[a-z0-9]+([-.][a-z0-9]+)*-[bcdfghjklmnpqrstvwxz2456789]{5}\b`), placeholder: "<name>"},
+	// Hexadecimal IDs, e.g. hashes.
+	{re: regexp.MustCompile(`\b[0-9a-f]{12,}\b`), placeholder: "<id>"},
+	// Numbers.
+	{re: regexp.MustCompile(`\d+`), placeholder: "<n>"},
+}
+
+// NormalizeMessage returns a message without the parts which are specific of an object, like object names,
+// IP addresses, IDs and numbers, so messages reporting the same problem for different objects can be compared.
+// NB. Occurrences of the name of the object reporting the message are normalized too.
+func NormalizeMessage(name, message string) string {
+	if name != "" {
+		message = strings.ReplaceAll(message, name, "<name>")
+	}
+	for _, n := range messageNormalizers {
+		message = n.re.ReplaceAllString(message, n.placeholder)
+	}
+	return message
+}
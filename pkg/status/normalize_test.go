@@ -0,0 +1,55 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_NormalizeMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		objName string
+		message string
+		want    string
+	}{
+		{
+			name:    "Object name",
+			objName: "my-machine",
+			message: "machine my-machine is not ready",
+			want:    "machine <name> is not ready",
+		},
+		{
+			name:    "Generated names",
+			message: "waiting for md-0-6d9c8f7b5-x2kqz",
+			want:    "waiting for <name>",
+		},
+		{
+			name:    "IP addresses",
+			message: "dial tcp 10.0.0.1:6443: connection refused, fd00:10:96::1 unreachable",
+			want:    "dial tcp <ip>: connection refused, <ip> unreachable",
+		},
+		{
+			name:    "IDs",
+			message: "instance i-0123456789abcdef0 for 6ba7b810-9dad-11d1-80b4-00c04fd430c8 not found",
+			want:    "instance <id> for <id> not found",
+		},
+		{
+			name:    "Numbers",
+			message: "1 of 3 replicas ready after 120s",
+			want:    "<n> of <n> replicas ready after <n>s",
+		},
+		{
+			name:    "Words are preserved",
+			message: "failed to create bootstrap data: timeout",
+			want:    "failed to create bootstrap data: timeout",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(NormalizeMessage(tt.objName, tt.message)).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package status
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// RollUp sets a ready condition for virtual nodes without one, e.g. workers, aggregating the ready conditions
+// of their descendants with the same semantic used by Cluster API: the status, severity and reason of the
+// worst ready condition, the number of descendants not ready and the oldest transition among them.
+// NB. Descendants are searched through virtual nodes and through nodes without a ready condition, e.g.
+// MachineDeployments, so the aggregation is computed on the ready conditions of the Machines.
+// NB. Group nodes and virtual nodes without descendants with a ready condition are not changed.
+func (od ObjectTree) RollUp() {
+	od.rollUp(od.root)
+}
+
+func (od ObjectTree) rollUp(node *Node) {
+	// Roll up children first, so the virtual descendants have a ready condition when aggregating the current node.
+	for _, child := range od.GetChildren(node.ID) {
+		od.rollUp(child)
+	}
+
+	if !node.Virtual || node.IsGroup() || node.GetReadyCondition() != nil {
+		return
+	}
+	if ready := aggregateReady(od.getReadyDescendants(node)); ready != nil {
+		node.setCondition(ready)
+	}
+}
+
+// getReadyDescendants returns the descendants of a node to be aggregated when rolling up the ready condition,
+// that are the nearest descendants with a ready condition, not considering virtual nodes other than groups.
+func (od ObjectTree) getReadyDescendants(node *Node) []*Node {
+	var out []*Node
+	for _, child := range od.GetChildren(node.ID) {
+		if child.IsGroup() || (!child.Virtual && child.GetReadyCondition() != nil) {
+			out = append(out, child)
+			continue
+		}
+		out = append(out, od.getReadyDescendants(child)...)
+	}
+	return out
+}
+
+// aggregateReady returns the aggregate ready condition for a list of nodes, or nil if none of the nodes
+// has a ready condition.
+func aggregateReady(nodes []*Node) *clusterv1.Condition {
+	// Compute status, severity and reason using Cluster API aggregation.
+	// NB. Nodes are wrapped into Cluster objects, given that aggregation works on condition getters.
+	var from []conditions.Getter
+	for _, n := range nodes {
+		from = append(from, &clusterv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				Kind: n.Kind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: n.Name,
+			},
+			Status: clusterv1.ClusterStatus{
+				Conditions: n.Conditions,
+			},
+		})
+	}
+	to := &clusterv1.Cluster{}
+	conditions.SetAggregate(to, clusterv1.ReadyCondition, from, conditions.AddSourceRef())
+	ready := conditions.Get(to, clusterv1.ReadyCondition)
+	if ready == nil {
+		return nil
+	}
+
+	// Count the nodes not ready, and compute the oldest transition among them or, if all the nodes are ready,
+	// the most recent transition.
+	// NB. Group nodes are counted for the number of items in the group, using the group's transition times.
+	total, notReady := 0, 0
+	var oldestNotReady, newestReady metav1.Time
+	for _, n := range nodes {
+		nReady := n.GetReadyCondition()
+		if nReady == nil {
+			continue
+		}
+		items := 1
+		oldest, newest := nReady.LastTransitionTime, nReady.LastTransitionTime
+		if n.IsGroup() {
+			items = len(n.GroupItems)
+			if n.GroupStats != nil {
+				oldest, newest = n.GroupStats.OldestTransition, n.GroupStats.NewestTransition
+			}
+		}
+		total += items
+
+		if nReady.Status == corev1.ConditionTrue {
+			if newestReady.IsZero() || newestReady.Before(&newest) {
+				newestReady = newest
+			}
+			continue
+		}
+		notReady += items
+		if oldestNotReady.IsZero() || oldest.Before(&oldestNotReady) {
+			oldestNotReady = oldest
+		}
+	}
+
+	if notReady == 0 {
+		ready.LastTransitionTime = newestReady
+		return ready
+	}
+	ready.LastTransitionTime = oldestNotReady
+	ready.Message = fmt.Sprintf("%d of %d not ready", notReady, total)
+	return ready
+}
@@ -0,0 +1,131 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeRollUp(t *testing.T) {
+	now := time.Now()
+	withTime := func(c *clusterv1.Condition, d time.Duration) *clusterv1.Condition {
+		c.LastTransitionTime = metav1.NewTime(now.Add(-d))
+		return c
+	}
+	readyTrue := func(d time.Duration) *clusterv1.Condition {
+		return withTime(conditions.TrueCondition(clusterv1.ReadyCondition), d)
+	}
+	readyFalse := func(severity clusterv1.ConditionSeverity, d time.Duration) *clusterv1.Condition {
+		return withTime(conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", severity, ""), d)
+	}
+	// NB. In Cluster API v1alpha3 MachineDeployments do not have conditions.
+	addMachineDeployment := func(objs *ObjectTree, workers *Node, name string, machines ...*clusterv1.Machine) {
+		mdNode := objs.Add(workers, testMachineDeployment(name), GroupingObject(true))
+		for _, m := range machines {
+			objs.Add(mdNode, m)
+		}
+	}
+
+	tests := []struct {
+		name         string
+		addWorkers   func(objs *ObjectTree, workers *Node)
+		group        bool
+		wantNil      bool
+		wantStatus   corev1.ConditionStatus
+		wantSeverity clusterv1.ConditionSeverity
+		wantReason   string
+		wantMessage  string
+		wantAge      time.Duration
+	}{
+		{
+			name: "No ready conditions",
+			addWorkers: func(objs *ObjectTree, workers *Node) {
+				addMachineDeployment(objs, workers, "md1", testMachine("m1", nil))
+			},
+			wantNil: true,
+		},
+		{
+			name: "All machines ready",
+			addWorkers: func(objs *ObjectTree, workers *Node) {
+				addMachineDeployment(objs, workers, "md1", testMachine("m1", readyTrue(time.Hour)))
+				addMachineDeployment(objs, workers, "md2", testMachine("m2", readyTrue(time.Minute)), testMachine("m3", nil))
+			},
+			wantStatus: corev1.ConditionTrue,
+			wantAge:    time.Minute,
+		},
+		{
+			name: "Worst severity and oldest transition among machines not ready",
+			addWorkers: func(objs *ObjectTree, workers *Node) {
+				addMachineDeployment(objs, workers, "md1", testMachine("m1", readyTrue(time.Minute)))
+				addMachineDeployment(objs, workers, "md2",
+					testMachine("m2", readyFalse(clusterv1.ConditionSeverityInfo, 2*time.Hour)),
+					testMachine("m3", readyFalse(clusterv1.ConditionSeverityWarning, time.Hour)))
+			},
+			wantStatus:   corev1.ConditionFalse,
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+			wantReason:   "Reason@Machine/m3",
+			wantMessage:  "2 of 3 not ready",
+			wantAge:      2 * time.Hour,
+		},
+		{
+			name: "Machines in virtual nodes are aggregated",
+			addWorkers: func(objs *ObjectTree, workers *Node) {
+				addMachineDeployment(objs, workers, "md1", testMachine("m1", readyTrue(time.Minute)))
+				other := objs.AddVirtual(workers, "Other")
+				objs.Add(other, testMachine("m2", readyFalse(clusterv1.ConditionSeverityInfo, time.Hour)))
+			},
+			wantStatus:   corev1.ConditionFalse,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantReason:   "Reason@Machine/m2",
+			wantMessage:  "1 of 2 not ready",
+			wantAge:      time.Hour,
+		},
+		{
+			name: "Groups are counted for their items",
+			addWorkers: func(objs *ObjectTree, workers *Node) {
+				addMachineDeployment(objs, workers, "md1", testMachine("m1", readyTrue(time.Hour)))
+				addMachineDeployment(objs, workers, "md2",
+					testMachine("m2", readyFalse(clusterv1.ConditionSeverityInfo, time.Minute)),
+					testMachine("m3", readyFalse(clusterv1.ConditionSeverityInfo, time.Minute)),
+					testMachine("m4", readyFalse(clusterv1.ConditionSeverityInfo, time.Minute)))
+			},
+			group:        true,
+			wantStatus:   corev1.ConditionFalse,
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+			wantMessage:  "3 of 4 not ready",
+			wantAge:      time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+			workers := objs.AddVirtual(objs.GetRoot(), "Workers")
+			tt.addWorkers(objs, workers)
+			if tt.group {
+				objs.Group()
+			}
+			objs.RollUp()
+
+			got := workers.GetReadyCondition()
+			if tt.wantNil {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).ToNot(BeNil())
+			g.Expect(got.Status).To(Equal(tt.wantStatus))
+			g.Expect(got.Severity).To(Equal(tt.wantSeverity))
+			if tt.wantReason != "" {
+				g.Expect(got.Reason).To(Equal(tt.wantReason))
+			}
+			g.Expect(got.Message).To(Equal(tt.wantMessage))
+			g.Expect(got.LastTransitionTime.Time).To(BeTemporally("~", now.Add(-tt.wantAge), time.Second))
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+// StuckThresholds defines how long a condition can be False or Unknown before being considered stuck;
+// thresholds for the condition reason take precedence over thresholds for the object kind, and those
+// over the default threshold.
+type StuckThresholds struct {
+	// Default threshold, used when there are no thresholds for the condition reason or for the object kind.
+	Default time.Duration
+
+	// Reasons defines thresholds for condition reasons, e.g. WaitingForInfrastructure.
+	Reasons map[string]time.Duration
+
+	// Kinds defines thresholds for object kinds, e.g. Machine.
+	Kinds map[string]time.Duration
+}
+
+// DefaultStuckThresholds returns the default thresholds for stuck conditions.
+func DefaultStuckThresholds() *StuckThresholds {
+	return &StuckThresholds{
+		Default: 30 * time.Minute,
+		Reasons: map[string]time.Duration{
+			clusterv1.WaitingForInfrastructureFallbackReason: 15 * time.Minute,
+			clusterv1.WaitingForControlPlaneFallbackReason:   20 * time.Minute,
+			clusterv1.WaitingForDataSecretFallbackReason:     10 * time.Minute,
+			clusterv1.WaitingForRemediation:                  10 * time.Minute,
+			"DrainingFailed":                                 10 * time.Minute,
+			"WaitingForKubeadmInit":                          20 * time.Minute,
+			"ScalingUp":                                      20 * time.Minute,
+			"ScalingDown":                                    20 * time.Minute,
+			"RollingUpdateInProgress":                        60 * time.Minute,
+		},
+		Kinds: map[string]time.Duration{
+			"Machine": 20 * time.Minute,
+		},
+	}
+}
+
+// ParseStuckThresholds parses a list of comma separated thresholds overriding the default thresholds, e.g.
+// default=45m,reason:WaitingForInfrastructure=20m,kind:Machine=30m.
+func ParseStuckThresholds(s string) (*StuckThresholds, error) {
+	thresholds := DefaultStuckThresholds()
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		kv := strings.SplitN(term, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid stuck threshold %q, thresholds must be in the form key=duration", term)
+		}
+		key := strings.TrimSpace(kv[0])
+		d, err := time.ParseDuration(strings.TrimSpace(kv[1]))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration %q for stuck threshold %q", kv[1], key)
+		}
+
+		switch {
+		case key == "default":
+			thresholds.Default = d
+		case strings.HasPrefix(key, "reason:") && len(key) > len("reason:"):
+			thresholds.Reasons[strings.TrimPrefix(key, "reason:")] = d
+		case strings.HasPrefix(key, "kind:") && len(key) > len("kind:"):
+			thresholds.Kinds[strings.TrimPrefix(key, "kind:")] = d
+		default:
+			return nil, fmt.Errorf("invalid stuck threshold key %q, valid keys are default, reason:<Reason> and kind:<Kind>", key)
+		}
+	}
+	return thresholds, nil
+}
+
+// threshold returns the threshold for a condition with the given reason on an object of the given kind.
+// NB. Reasons with a source reference, e.g. WaitingForInfrastructure@Machine/m1, are looked up without the reference.
+func (t *StuckThresholds) threshold(kind, reason string) time.Duration {
+	if d, ok := t.Reasons[trimSourceRef(reason)]; ok {
+		return d
+	}
+	for k, d := range t.Kinds {
+		if strings.EqualFold(k, kind) {
+			return d
+		}
+	}
+	return t.Default
+}
+
+// DetectStuck sets the conditions which are False or Unknown for longer than the given thresholds as stuck
+// for all the nodes in the tree.
+// NB. For group nodes the oldest transition among the objects in the group is used.
+func (od ObjectTree) DetectStuck(thresholds *StuckThresholds) {
+	od.detectStuck(thresholds, time.Now())
+}
+
+func (od ObjectTree) detectStuck(thresholds *StuckThresholds, now time.Time) {
+	od.Walk(func(node *Node, _ int) bool {
+		node.StuckConditions = nil
+		for _, c := range node.Conditions {
+			if c.Status == corev1.ConditionTrue || c.LastTransitionTime.IsZero() {
+				continue
+			}
+			since := c.LastTransitionTime.Time
+			if c.Type == clusterv1.ReadyCondition && node.GroupStats != nil && !node.GroupStats.OldestTransition.IsZero() {
+				since = node.GroupStats.OldestTransition.Time
+			}
+			if now.Sub(since) > thresholds.threshold(node.Kind, c.Reason) {
+				node.StuckConditions = append(node.StuckConditions, c.Type)
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,72 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ParseStuckThresholds(t *testing.T) {
+	g := NewWithT(t)
+
+	got, err := ParseStuckThresholds("default=45m, reason:Foo=1h,kind:MachineDeployment=5m")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got.threshold("Cluster", "Bar")).To(Equal(45 * time.Minute))
+	g.Expect(got.threshold("MachineDeployment", "Foo")).To(Equal(time.Hour))
+	g.Expect(got.threshold("machinedeployment", "Bar")).To(Equal(5 * time.Minute))
+	g.Expect(got.threshold("Machine", "WaitingForInfrastructure@Machine/m1")).To(Equal(15 * time.Minute))
+
+	for _, s := range []string{"default", "foo=1h", "reason:=1h", "default=abc", "default=-1h"} {
+		_, err := ParseStuckThresholds(s)
+		g.Expect(err).To(HaveOccurred(), s)
+	}
+}
+
+func Test_ObjectTreeDetectStuck(t *testing.T) {
+	now := time.Now()
+	condition := func(c *clusterv1.Condition, d time.Duration) clusterv1.Condition {
+		c.LastTransitionTime = metav1.NewTime(now.Add(-d))
+		return *c
+	}
+
+	tests := []struct {
+		name       string
+		conditions clusterv1.Conditions
+		want       []clusterv1.ConditionType
+	}{
+		{
+			name:       "True conditions are never stuck",
+			conditions: clusterv1.Conditions{condition(conditions.TrueCondition(clusterv1.ReadyCondition), 2*time.Hour)},
+			want:       nil,
+		},
+		{
+			name:       "False conditions within the threshold are not stuck",
+			conditions: clusterv1.Conditions{condition(conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityInfo, ""), 20*time.Minute)},
+			want:       nil,
+		},
+		{
+			name: "False conditions over the threshold are stuck",
+			conditions: clusterv1.Conditions{
+				condition(conditions.FalseCondition(clusterv1.ReadyCondition, "Foo", clusterv1.ConditionSeverityInfo, ""), 40*time.Minute),
+				condition(conditions.UnknownCondition(clusterv1.InfrastructureReadyCondition, clusterv1.WaitingForInfrastructureFallbackReason, ""), 20*time.Minute),
+			},
+			want: []clusterv1.ConditionType{clusterv1.ReadyCondition, clusterv1.InfrastructureReadyCondition},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cluster := testCluster()
+			cluster.Status.Conditions = tt.conditions
+			objs := NewObjectTree(cluster, ObjectTreeOptions{})
+			objs.detectStuck(DefaultStuckThresholds(), now)
+
+			g.Expect(objs.GetRoot().StuckConditions).To(Equal(tt.want))
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package status
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+const (
+	// TimelineCreated is the step used in the timeline for the creation of an object.
+	TimelineCreated = "Created"
+
+	// TimelineDeleted is the step used in the timeline for the deletion of an object.
+	TimelineDeleted = "Deleted"
+)
+
+// TimelineEntry is a step in the life of an object, e.g. the object creation or a condition transition.
+type TimelineEntry struct {
+	// Time of the step.
+	Time metav1.Time
+
+	// Node the step refers to.
+	Node *Node
+
+	// Step is the condition type for condition transitions, TimelineCreated or TimelineDeleted.
+	Step string
+
+	// Status, Severity, Reason and Message of the condition after the transition; empty for the other steps.
+	Status   corev1.ConditionStatus
+	Severity clusterv1.ConditionSeverity
+	Reason   string
+	Message  string
+}
+
+// Timeline returns the creation and deletion time of all the objects in the tree, and the last transition time
+// of all their conditions, sorted in chronological order.
+// NB. Conditions only record the last transition, so previous transitions are not included in the timeline.
+func (od ObjectTree) Timeline() []TimelineEntry {
+	var entries []TimelineEntry
+	od.Walk(func(node *Node, _ int) bool {
+		if node.Object == nil {
+			return true
+		}
+
+		if t := node.Object.GetCreationTimestamp(); !t.IsZero() {
+			entries = append(entries, TimelineEntry{
+				Time: t,
+				Node: node,
+				Step: TimelineCreated,
+			})
+		}
+		if t := node.Object.GetDeletionTimestamp(); t != nil {
+			entries = append(entries, TimelineEntry{
+				Time: *t,
+				Node: node,
+				Step: TimelineDeleted,
+			})
+		}
+		for _, c := range node.Conditions {
+			if c.LastTransitionTime.IsZero() {
+				continue
+			}
+			entries = append(entries, TimelineEntry{
+				Time:     c.LastTransitionTime,
+				Node:     node,
+				Step:     string(c.Type),
+				Status:   c.Status,
+				Severity: c.Severity,
+				Reason:   c.Reason,
+				Message:  c.Message,
+			})
+		}
+		return true
+	})
+
+	// NB. Entries with the same time preserve the order of the tree walk, so e.g. a Created step
+	// is shown before the conditions of the same object.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Time.Before(&entries[j].Time)
+	})
+	return entries
+}
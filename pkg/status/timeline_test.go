@@ -0,0 +1,46 @@
+package status
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_ObjectTreeTimeline(t *testing.T) {
+	g := NewWithT(t)
+
+	start := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+	at := func(d time.Duration) metav1.Time {
+		return metav1.NewTime(start.Add(d))
+	}
+
+	cluster := testCluster()
+	cluster.CreationTimestamp = at(0)
+	clusterReady := conditions.TrueCondition(clusterv1.ReadyCondition)
+	clusterReady.LastTransitionTime = at(20 * time.Minute)
+	conditions.Set(cluster, clusterReady)
+
+	machine := testMachine("m1", nil)
+	machine.CreationTimestamp = at(time.Minute)
+	bootstrapReady := conditions.TrueCondition(clusterv1.BootstrapReadyCondition)
+	bootstrapReady.LastTransitionTime = at(5 * time.Minute)
+	conditions.Set(machine, bootstrapReady)
+
+	objs := NewObjectTree(cluster, ObjectTreeOptions{})
+	objs.Add(objs.GetRoot(), machine)
+
+	var got []string
+	for _, e := range objs.Timeline() {
+		got = append(got, e.Node.Name+"/"+e.Step)
+	}
+	g.Expect(got).To(Equal([]string{
+		"cluster/" + TimelineCreated,
+		"m1/" + TimelineCreated,
+		"m1/" + string(clusterv1.BootstrapReadyCondition),
+		"cluster/" + string(clusterv1.ReadyCondition),
+	}))
+}
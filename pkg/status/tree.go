@@ -0,0 +1,290 @@
+package status
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ObjectTreeOptions defines options for building an ObjectTree.
+type ObjectTreeOptions struct {
+	// ShowOtherConditions is a list of comma separated kind or kind/name for which we should set ShowConditions
+	// to signal to the presentation layer to show all the conditions for the objects.
+	ShowOtherConditions string
+
+	// DisableNoEcho disables hiding objects if the object's ready condition has the
+	// same Status, Severity and Reason of the parent's object ready condition (it is an echo)
+	DisableNoEcho bool
+
+	// DisableGroupObjects disables grouping sibling objects in case the ready condition
+	// has the same Status, Severity and Reason
+	DisableGroupObjects bool
+
+	// GroupBy is the list of keys that sibling objects must have in common in order to be grouped,
+	// e.g. reason or version; if empty, DefaultGroupBy is used.
+	GroupBy []string
+
+	// GroupingMessagePattern, if defined, is applied to the message of the ready condition when grouping
+	// by message; objects are grouped if the part of the message matching the pattern (or its first
+	// sub-match) is the same.
+	GroupingMessagePattern *regexp.Regexp
+
+	// GroupingMinSize is the minimum number of objects for creating a group; if lower than 2, 2 is used.
+	GroupingMinSize int
+
+	// GroupingParents is a list of kinds for which the children should be grouped; if empty,
+	// children are grouped for the objects added with the GroupingObject option.
+	GroupingParents []string
+
+	// DebugFilter is a list of kind or kind/name for which we should set ShowConditions.
+	DebugFilter string
+}
+
+// ObjectTree defines an object tree representing the status of a Cluster API cluster.
+type ObjectTree struct {
+	root      *Node
+	options   ObjectTreeOptions
+	items     map[types.UID]*Node
+	ownership map[types.UID]map[types.UID]bool
+}
+
+// NewObjectTree creates a new object tree with the given root, e.g. a Cluster.
+func NewObjectTree(root controllerutil.Object, options ObjectTreeOptions) *ObjectTree {
+	od := &ObjectTree{
+		root:      newNode(root),
+		options:   options,
+		items:     make(map[types.UID]*Node),
+		ownership: make(map[types.UID]map[types.UID]bool),
+	}
+	od.root.ShowConditions = isNodeDebug(od.root, options.ShowOtherConditions)
+	// NB. The root is not added with add, so the list of grouping parents is applied here.
+	od.root.Grouping = containsKind(options.GroupingParents, od.root.Kind) && !options.DisableGroupObjects
+	return od
+}
+
+// Add adds an object to the object tree as a child of parent; objects could be hidden or grouped with their siblings
+// according to the tree options and the given add options.
+// Add returns the node for the object, or nil if the object is hidden.
+func (od ObjectTree) Add(parent *Node, obj controllerutil.Object, opts ...AddObjectOption) *Node {
+	return od.add(parent, newNode(obj), opts...)
+}
+
+// AddVirtual adds a virtual node with the given name to the object tree as a child of parent, e.g. workers.
+func (od ObjectTree) AddVirtual(parent *Node, name string, opts ...AddObjectOption) *Node {
+	return od.add(parent, newVirtualNode(parent.Namespace, name), opts...)
+}
+
+// Complete completes the object tree once all the objects are added, rolling up the status of virtual nodes,
+// checking consistency, grouping objects and detecting stuck conditions; if thresholds is nil, DefaultStuckThresholds
+// are used.
+// NB. Consistency is checked before grouping, because grouping removes the descendants of grouped nodes.
+func (od ObjectTree) Complete(thresholds *StuckThresholds) {
+	od.RollUp()
+	od.CheckConsistency()
+	od.Group()
+
+	if thresholds == nil {
+		thresholds = DefaultStuckThresholds()
+	}
+	od.DetectStuck(thresholds)
+}
+
+func (od ObjectTree) add(parent, node *Node, opts ...AddObjectOption) *Node {
+	addOpts := &AddObjectOptions{}
+	addOpts.ApplyOptions(opts)
+
+	nodeReady := node.GetReadyCondition()
+
+	// If it is requested to show all the conditions for the object, set ShowConditions
+	// to signal this to the presentation layer.
+	node.ShowConditions = isNodeDebug(node, od.options.ShowOtherConditions)
+
+	// If the object should be hidden if the object's ready condition is true ot it has the
+	// same Status, Severity and Reason of the parent's object ready condition (it is an echo),
+	// return early.
+	// NB. Objects reporting a terminal failure are never hidden.
+	// NB. Hidden objects are kept on the parent, so they can be used for checking the consistency of the parent's conditions.
+	// NB. Echoes are detected using the ready conditions as stored in the objects, because the severity inferred for
+	// unstructured objects is not mirrored into the parent's ready condition.
+	if addOpts.NoEcho && !od.options.DisableNoEcho && !node.IsFailed() {
+		if (nodeReady != nil && nodeReady.Status == corev1.ConditionTrue) || hasSameReadyStatusSeverityAndReason(parent.storedReadyCondition(), node.storedReadyCondition()) {
+			parent.HiddenChildren = append(parent.HiddenChildren, node)
+			return nil
+		}
+	}
+
+	// If it is requested to use a meta name for the object in the presentation layer, set MetaName
+	// to signal this to the presentation layer.
+	node.MetaName = addOpts.MetaName
+
+	// If it is requested that the children of this node should be grouped, set Grouping to signal this to Group.
+	// NB. If the list of grouping parents is defined, it overrides the add options.
+	node.Grouping = addOpts.GroupingObject
+	if len(od.options.GroupingParents) > 0 {
+		node.Grouping = containsKind(od.options.GroupingParents, node.Kind)
+	}
+	node.Grouping = node.Grouping && !od.options.DisableGroupObjects
+
+	// Add the object to the object tree.
+	od.addInner(parent, node)
+	return node
+}
+
+func (od ObjectTree) remove(parent *Node, s *Node) {
+	delete(od.items, s.ID)
+	delete(od.ownership[parent.ID], s.ID)
+	delete(od.ownership, s.ID)
+}
+
+func (od ObjectTree) addInner(parent *Node, node *Node) {
+	od.items[node.ID] = node
+	if od.ownership[parent.ID] == nil {
+		od.ownership[parent.ID] = make(map[types.UID]bool)
+	}
+	od.ownership[parent.ID][node.ID] = true
+}
+
+// GetRoot returns the root of the object tree.
+func (od ObjectTree) GetRoot() *Node { return od.root }
+
+// IsPaused returns true if the reconciliation of all the objects in the tree is paused, because the root
+// is a Cluster with Spec.Paused set.
+// NB. The paused annotation on the Cluster pauses only the reconciliation of the Cluster itself.
+func (od ObjectTree) IsPaused() bool {
+	cluster, ok := od.root.Object.(*clusterv1.Cluster)
+	return ok && cluster.Spec.Paused
+}
+
+// GetNode returns a node in the tree, if any.
+func (od ObjectTree) GetNode(id types.UID) *Node {
+	if od.root.ID == id {
+		return od.root
+	}
+	return od.items[id]
+}
+
+// GetChildren returns the children of a node.
+func (od ObjectTree) GetChildren(id types.UID) []*Node {
+	var out []*Node
+	for k := range od.ownership[id] {
+		out = append(out, od.GetNode(k))
+	}
+	return out
+}
+
+// GetParent returns the parent of a node, or nil if the node is the root or it is not in the tree.
+func (od ObjectTree) GetParent(id types.UID) *Node {
+	for parent, children := range od.ownership {
+		if children[id] {
+			return od.GetNode(parent)
+		}
+	}
+	return nil
+}
+
+// WalkFunc is the type of the function called for each node visited by Walk; depth is 0 for the root.
+// If the function returns false, the children of the node are not visited.
+type WalkFunc func(node *Node, depth int) bool
+
+// Walk visits all the nodes in the tree in depth-first order, starting from the root; the children of
+// each node are visited in order of kind and name.
+func (od ObjectTree) Walk(fn WalkFunc) {
+	od.walk(od.root, 0, fn)
+}
+
+func (od ObjectTree) walk(node *Node, depth int, fn WalkFunc) {
+	if !fn(node, depth) {
+		return
+	}
+
+	children := od.GetChildren(node.ID)
+	sort.Slice(children, func(i, j int) bool {
+		if children[i].Kind != children[j].Kind {
+			return children[i].Kind < children[j].Kind
+		}
+		return children[i].Name < children[j].Name
+	})
+	for _, child := range children {
+		od.walk(child, depth+1, fn)
+	}
+}
+
+// Filter removes from the tree all the nodes that do not match and that do not have any matching descendant,
+// thus keeping the matching nodes and their ancestors; the root is never removed.
+func (od ObjectTree) Filter(match func(node *Node) bool) {
+	od.filter(od.root, match)
+}
+
+func (od ObjectTree) filter(node *Node, match func(node *Node) bool) bool {
+	keep := match(node)
+	for _, child := range od.GetChildren(node.ID) {
+		if od.filter(child, match) {
+			keep = true
+			continue
+		}
+		od.remove(node, child)
+	}
+	return keep
+}
+
+// GetBlockingNodes returns the nodes in the tree that are blocking the deletion of a node,
+// that are its children, including the children hidden because of the NoEcho option, or, for virtual
+// children, their descendants.
+func (od ObjectTree) GetBlockingNodes(id types.UID) []*Node {
+	var out []*Node
+	var hidden []*Node
+	if node := od.GetNode(id); node != nil {
+		hidden = node.HiddenChildren
+	}
+	for _, child := range append(od.GetChildren(id), hidden...) {
+		if child.Virtual && !child.IsGroup() {
+			out = append(out, od.GetBlockingNodes(child.ID)...)
+			continue
+		}
+		out = append(out, child)
+	}
+	return out
+}
+
+func hasSameReadyStatusSeverityAndReason(a, b *clusterv1.Condition) bool {
+	if a == nil && b == nil {
+		return true
+	}
+	if (a == nil) != (b == nil) {
+		return false
+	}
+
+	return a.Status == b.Status &&
+		a.Severity == b.Severity &&
+		a.Reason == b.Reason
+}
+
+func isNodeDebug(node *Node, debugFilter string) bool {
+	if debugFilter == "" {
+		return false
+	}
+	for _, filter := range strings.Split(debugFilter, ",") {
+		if filter == "" {
+			continue
+		}
+		if strings.ToLower(filter) == "all" {
+			return true
+		}
+		kn := strings.Split(filter, "/")
+		if len(kn) == 2 {
+			if node.Kind == kn[0] && node.Name == kn[1] {
+				return true
+			}
+			continue
+		}
+		if node.Kind == kn[0] {
+			return true
+		}
+	}
+	return false
+}
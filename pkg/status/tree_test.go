@@ -0,0 +1,235 @@
+package status
+
+import (
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_hasSameReadyStatusSeverityAndReason(t *testing.T) {
+	readyTrue := conditions.TrueCondition(clusterv1.ReadyCondition)
+	readyFalseReasonInfo := conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityInfo, "message falseInfo1")
+	readyFalseAnotherReasonInfo := conditions.FalseCondition(clusterv1.ReadyCondition, "AnotherReason", clusterv1.ConditionSeverityInfo, "message falseInfo1")
+	readyFalseReasonWarning := conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, "message falseInfo1")
+
+	type args struct {
+		a *clusterv1.Condition
+		b *clusterv1.Condition
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "Objects without conditions should group",
+			args: args{
+				a: nil,
+				b: nil,
+			},
+			want: true,
+		},
+		{
+			name: "Objects with same Ready condition",
+			args: args{
+				a: readyTrue,
+				b: readyTrue,
+			},
+			want: true,
+		},
+		{
+			name: "Objects with different Ready.Status",
+			args: args{
+				a: readyTrue,
+				b: readyFalseReasonInfo,
+			},
+			want: false,
+		},
+		{
+			name: "Objects with different Ready.Reason",
+			args: args{
+				a: readyFalseReasonInfo,
+				b: readyFalseAnotherReasonInfo,
+			},
+			want: false,
+		},
+		{
+			name: "Objects with different Ready.Severity",
+			args: args{
+				a: readyFalseReasonInfo,
+				b: readyFalseReasonWarning,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := hasSameReadyStatusSeverityAndReason(tt.args.a, tt.args.b)
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_ObjectTreeWalk(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := testCluster()
+
+	objs := NewObjectTree(cluster, ObjectTreeOptions{})
+	workers := objs.AddVirtual(objs.GetRoot(), "Workers")
+	objs.AddVirtual(workers, "md2")
+	md1 := objs.AddVirtual(workers, "md1")
+
+	g.Expect(objs.GetRoot().Object).To(Equal(cluster))
+	g.Expect(objs.GetParent(md1.ID)).To(Equal(workers))
+	g.Expect(objs.GetParent(objs.GetRoot().ID)).To(BeNil())
+
+	var visited []string
+	objs.Walk(func(node *Node, depth int) bool {
+		visited = append(visited, fmt.Sprintf("%d:%s", depth, node.Name))
+		return true
+	})
+	g.Expect(visited).To(Equal([]string{"0:cluster", "1:Workers", "2:md1", "2:md2"}))
+
+	visited = nil
+	objs.Walk(func(node *Node, depth int) bool {
+		visited = append(visited, node.Name)
+		return node.Name != "Workers"
+	})
+	g.Expect(visited).To(Equal([]string{"cluster", "Workers"}))
+}
+
+func Test_ObjectTreeGroup(t *testing.T) {
+	g := NewWithT(t)
+
+	m1 := testMachine("m1", conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityInfo, "message m1"))
+	m2 := testMachine("m2", conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityInfo, "message m2"))
+	m3 := testMachine("m3", conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityInfo, "message m3"))
+
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	controlPlane := objs.AddVirtual(objs.GetRoot(), "ControlPlane", GroupingObject(true))
+	objs.Add(controlPlane, m1)
+	objs.Add(controlPlane, m2)
+	objs.Add(controlPlane, m3)
+	objs.Group()
+
+	children := objs.GetChildren(controlPlane.ID)
+	g.Expect(children).To(HaveLen(1))
+
+	group := children[0]
+	g.Expect(group.IsGroup()).To(BeTrue())
+	g.Expect(group.Kind).To(Equal("Machine"))
+	g.Expect(group.GroupItems).To(Equal([]string{"m1", "m2", "m3"}))
+	g.Expect(group.GetReadyCondition().Reason).To(Equal("Reason"))
+	g.Expect(group.GetReadyCondition().Message).To(BeEmpty())
+
+	// Original objects should not be changed.
+	g.Expect(m1.GetAnnotations()).To(BeEmpty())
+	g.Expect(conditions.GetMessage(m1, clusterv1.ReadyCondition)).To(Equal("message m1"))
+}
+
+func Test_ObjectTreeFilterUnhealthy(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	controlPlane := objs.AddVirtual(objs.GetRoot(), "ControlPlane")
+	objs.Add(controlPlane, testMachine("cp1", conditions.TrueCondition(clusterv1.ReadyCondition)))
+	workers := objs.AddVirtual(objs.GetRoot(), "Workers")
+	md := objs.AddVirtual(workers, "md")
+	objs.Add(md, testMachine("w1", conditions.TrueCondition(clusterv1.ReadyCondition)))
+	objs.Add(md, testMachine("w2", conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityError, "")))
+
+	objs.Filter(func(node *Node) bool {
+		return !node.IsHealthy()
+	})
+
+	var visited []string
+	objs.Walk(func(node *Node, depth int) bool {
+		visited = append(visited, node.Name)
+		return true
+	})
+	g.Expect(visited).To(Equal([]string{"cluster", "Workers", "md", "w2"}))
+}
+
+func Test_ObjectTreeAddNoEcho(t *testing.T) {
+	tests := []struct {
+		name           string
+		machineReady   *clusterv1.Condition
+		infraCondition map[string]interface{}
+		wantHidden     bool
+	}{
+		{
+			name:           "Standard condition mirrored by the machine without severity is an echo",
+			machineReady:   conditions.FalseCondition(clusterv1.ReadyCondition, "InstanceNotReady", clusterv1.ConditionSeverityNone, ""),
+			infraCondition: map[string]interface{}{"type": "Ready", "status": "False", "reason": "InstanceNotReady", "observedGeneration": int64(1)},
+			wantHidden:     true,
+		},
+		{
+			name:           "Cluster API condition without severity mirrored by the machine is an echo",
+			machineReady:   conditions.FalseCondition(clusterv1.ReadyCondition, "InstanceNotReady", clusterv1.ConditionSeverityNone, ""),
+			infraCondition: map[string]interface{}{"type": "Ready", "status": "False", "reason": "InstanceNotReady"},
+			wantHidden:     true,
+		},
+		{
+			name:           "Cluster API condition with severity mirrored by the machine is an echo",
+			machineReady:   conditions.FalseCondition(clusterv1.ReadyCondition, "InstanceNotReady", clusterv1.ConditionSeverityWarning, ""),
+			infraCondition: map[string]interface{}{"type": "Ready", "status": "False", "reason": "InstanceNotReady", "severity": "Warning"},
+			wantHidden:     true,
+		},
+		{
+			name:           "Condition with a different reason is not an echo",
+			machineReady:   conditions.FalseCondition(clusterv1.ReadyCondition, "WaitingForBootstrapData", clusterv1.ConditionSeverityNone, ""),
+			infraCondition: map[string]interface{}{"type": "Ready", "status": "False", "reason": "InstanceNotReady", "observedGeneration": int64(1)},
+			wantHidden:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			infra := &unstructured.Unstructured{}
+			infra.SetKind("FooMachine")
+			infra.SetNamespace("ns")
+			infra.SetName("m1")
+			infra.SetUID("m1-infra")
+			g.Expect(unstructured.SetNestedSlice(infra.Object, []interface{}{tt.infraCondition}, "status", "conditions")).To(Succeed())
+
+			objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+			machineNode := objs.Add(objs.GetRoot(), testMachine("m1", tt.machineReady))
+			infraNode := objs.Add(machineNode, infra, NoEcho(true))
+
+			if tt.wantHidden {
+				g.Expect(infraNode).To(BeNil())
+				g.Expect(machineNode.HiddenChildren).To(HaveLen(1))
+				return
+			}
+			g.Expect(infraNode).ToNot(BeNil())
+			g.Expect(machineNode.HiddenChildren).To(BeEmpty())
+		})
+	}
+}
+
+func Test_ObjectTreeComplete(t *testing.T) {
+	g := NewWithT(t)
+
+	objs := NewObjectTree(testCluster(), ObjectTreeOptions{})
+	workers := objs.AddVirtual(objs.GetRoot(), "Workers")
+	md := objs.Add(workers, testMachineDeployment("md"), GroupingObject(true))
+	for _, name := range []string{"m1", "m2"} {
+		objs.Add(md, testMachine(name, conditions.FalseCondition(clusterv1.ReadyCondition, "Reason", clusterv1.ConditionSeverityWarning, "")))
+	}
+	objs.Complete(nil)
+
+	// Machines are rolled up into the virtual Workers node before being grouped.
+	g.Expect(workers.GetReadyCondition()).ToNot(BeNil())
+	g.Expect(workers.GetReadyCondition().Message).To(Equal("2 of 2 not ready"))
+	children := objs.GetChildren(md.ID)
+	g.Expect(children).To(HaveLen(1))
+	g.Expect(children[0].GroupItems).To(Equal([]string{"m1", "m2"}))
+}
@@ -0,0 +1,124 @@
+package status
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// getConditions returns a copy of the conditions for an object.
+func getConditions(obj controllerutil.Object) clusterv1.Conditions {
+	getter := objToGetter(obj)
+	if getter == nil {
+		return nil
+	}
+	return getter.GetConditions().DeepCopy()
+}
+
+// getStoredConditions returns the conditions for an object as stored in the object, without inferring the severity
+// for unstructured objects.
+func getStoredConditions(obj controllerutil.Object) clusterv1.Conditions {
+	getter := objToGetter(obj)
+	if getter == nil {
+		return nil
+	}
+	if u, ok := getter.(*unstructuredGetter); ok {
+		return conditions.UnstructuredGetter(u.Unstructured).GetConditions()
+	}
+	return getter.GetConditions()
+}
+
+// hasConditions returns true if the object has conditions in its API, e.g. false for MachineDeployments in
+// Cluster API v1alpha3; for unstructured objects, this is true if the object has status.conditions.
+func hasConditions(obj controllerutil.Object) bool {
+	getter := objToGetter(obj)
+	if u, ok := getter.(*unstructuredGetter); ok {
+		_, found, err := unstructured.NestedFieldNoCopy(u.Object, "status", "conditions")
+		return err == nil && found
+	}
+	return getter != nil
+}
+
+// objToGetter returns a condition getter for an object; objects that do not implement the getter interface are read
+// as unstructured, so conditions are read from status.conditions, both for conditions in the Cluster API format
+// and for conditions in the Kubernetes standard format (metav1.Condition).
+func objToGetter(obj controllerutil.Object) conditions.Getter {
+	if getter, ok := obj.(conditions.Getter); ok {
+		return getter
+	}
+
+	objUnstructured, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return nil
+		}
+		objUnstructured = &unstructured.Unstructured{Object: content}
+	}
+	return &unstructuredGetter{Unstructured: objUnstructured}
+}
+
+// unstructuredGetter is a condition getter for unstructured objects supporting conditions in the Kubernetes
+// standard format (metav1.Condition).
+type unstructuredGetter struct {
+	*unstructured.Unstructured
+}
+
+// GetConditions returns the conditions for an unstructured object.
+// NB. Conditions in the Kubernetes standard format, as well as Cluster API conditions set by providers not
+// following the contract, do not have a severity, so for conditions not True it is inferred from the status,
+// Warning for False and Info for Unknown, given that without a severity errors cannot be distinguished from
+// normal states of reconciliation.
+func (u *unstructuredGetter) GetConditions() clusterv1.Conditions {
+	conds := conditions.UnstructuredGetter(u.Unstructured).GetConditions()
+	for i := range conds {
+		c := &conds[i]
+		if c.Severity != clusterv1.ConditionSeverityNone {
+			continue
+		}
+		switch c.Status {
+		case corev1.ConditionFalse:
+			c.Severity = clusterv1.ConditionSeverityWarning
+		case corev1.ConditionUnknown:
+			c.Severity = clusterv1.ConditionSeverityInfo
+		}
+	}
+	return conds
+}
+
+// getStandardConditionsObservedGeneration returns the observed generation of the conditions of an unstructured
+// object in the Kubernetes standard format (metav1.Condition), that are the conditions with an observed generation.
+func getStandardConditionsObservedGeneration(u *unstructured.Unstructured) map[clusterv1.ConditionType]int64 {
+	items, ok, err := unstructured.NestedSlice(u.Object, "status", "conditions")
+	if err != nil || !ok {
+		return nil
+	}
+	observedGenerations := map[clusterv1.ConditionType]int64{}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		t, _, _ := unstructured.NestedString(m, "type")
+		observedGeneration, ok, err := unstructured.NestedInt64(m, "observedGeneration")
+		if t == "" || err != nil || !ok {
+			continue
+		}
+		observedGenerations[clusterv1.ConditionType(t)] = observedGeneration
+	}
+	return observedGenerations
+}
+
+// trimSourceRef returns a condition reason without the source reference added by Cluster API when
+// aggregating conditions, e.g. WaitingForInfrastructure for WaitingForInfrastructure@Machine/m1.
+func trimSourceRef(reason string) string {
+	if i := strings.Index(reason, "@"); i >= 0 {
+		return reason[:i]
+	}
+	return reason
+}
@@ -0,0 +1,67 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+)
+
+func Test_getConditions(t *testing.T) {
+	tests := []struct {
+		name         string
+		condition    map[string]interface{}
+		wantSeverity clusterv1.ConditionSeverity
+	}{
+		{
+			name:         "Cluster API condition True",
+			condition:    map[string]interface{}{"type": "Ready", "status": "True", "reason": "Foo"},
+			wantSeverity: clusterv1.ConditionSeverityNone,
+		},
+		{
+			name:         "Cluster API condition False without severity",
+			condition:    map[string]interface{}{"type": "Ready", "status": "False", "reason": "Foo"},
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:         "Cluster API condition Unknown without severity",
+			condition:    map[string]interface{}{"type": "Ready", "status": "Unknown", "reason": "Foo"},
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+		},
+		{
+			name:         "Cluster API condition False with severity",
+			condition:    map[string]interface{}{"type": "Ready", "status": "False", "reason": "Foo", "severity": "Error"},
+			wantSeverity: clusterv1.ConditionSeverityError,
+		},
+		{
+			name:         "Standard condition True",
+			condition:    map[string]interface{}{"type": "Ready", "status": "True", "reason": "Foo", "observedGeneration": int64(1)},
+			wantSeverity: clusterv1.ConditionSeverityNone,
+		},
+		{
+			name:         "Standard condition False",
+			condition:    map[string]interface{}{"type": "Ready", "status": "False", "reason": "Foo", "observedGeneration": int64(1)},
+			wantSeverity: clusterv1.ConditionSeverityWarning,
+		},
+		{
+			name:         "Standard condition Unknown",
+			condition:    map[string]interface{}{"type": "Ready", "status": "Unknown", "reason": "Foo", "observedGeneration": int64(1)},
+			wantSeverity: clusterv1.ConditionSeverityInfo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			_ = unstructured.SetNestedSlice(u.Object, []interface{}{tt.condition}, "status", "conditions")
+
+			got := getConditions(u)
+			g.Expect(got).To(HaveLen(1))
+			g.Expect(got[0].Type).To(Equal(clusterv1.ReadyCondition))
+			g.Expect(got[0].Reason).To(Equal("Foo"))
+			g.Expect(got[0].Severity).To(Equal(tt.wantSeverity))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package status
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// VersionRollout describes the progress of a version rollout for a control plane or a MachineDeployment.
+type VersionRollout struct {
+	// Version is the desired Kubernetes version.
+	Version string `json:"version"`
+
+	// UpToDate is the number of machines with the desired Kubernetes version.
+	UpToDate int `json:"upToDate"`
+
+	// Total is the number of machines.
+	Total int `json:"total"`
+}
+
+// GetVersion returns the Kubernetes version for an object, if defined.
+// For control planes and MachineDeployments this is the desired version for the machines.
+func GetVersion(obj controllerutil.Object) string {
+	switch o := obj.(type) {
+	case *clusterv1.Machine:
+		if o.Spec.Version != nil {
+			return *o.Spec.Version
+		}
+	case *clusterv1.MachineDeployment:
+		if o.Spec.Template.Spec.Version != nil {
+			return *o.Spec.Template.Spec.Version
+		}
+	case *unstructured.Unstructured:
+		if version, ok, err := unstructured.NestedString(o.Object, "spec", "version"); err == nil && ok {
+			return version
+		}
+	}
+	return ""
+}
+
+func newVersionRollout(obj controllerutil.Object, machines []*clusterv1.Machine) *VersionRollout {
+	version := GetVersion(obj)
+	if version == "" {
+		return nil
+	}
+
+	rollout := &VersionRollout{
+		Version: version,
+		Total:   len(machines),
+	}
+	for _, m := range machines {
+		if GetVersion(m) == version {
+			rollout.UpToDate++
+		}
+	}
+	return rollout
+}
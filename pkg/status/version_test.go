@@ -0,0 +1,61 @@
+package status
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1alpha3"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_newVersionRollout(t *testing.T) {
+	machine := func(version string) *clusterv1.Machine {
+		return &clusterv1.Machine{
+			Spec: clusterv1.MachineSpec{
+				Version: &version,
+			},
+		}
+	}
+	controlPlane := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"version": "v1.19.1",
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		obj      controllerutil.Object
+		machines []*clusterv1.Machine
+		want     *VersionRollout
+	}{
+		{
+			name:     "Object without version",
+			obj:      &unstructured.Unstructured{Object: map[string]interface{}{}},
+			machines: []*clusterv1.Machine{machine("v1.19.1")},
+			want:     nil,
+		},
+		{
+			name:     "Rollout completed",
+			obj:      controlPlane,
+			machines: []*clusterv1.Machine{machine("v1.19.1"), machine("v1.19.1")},
+			want:     &VersionRollout{Version: "v1.19.1", UpToDate: 2, Total: 2},
+		},
+		{
+			name:     "Rollout in progress",
+			obj:      controlPlane,
+			machines: []*clusterv1.Machine{machine("v1.18.2"), machine("v1.19.1"), machine("v1.18.2")},
+			want:     &VersionRollout{Version: "v1.19.1", UpToDate: 1, Total: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := newVersionRollout(tt.obj, tt.machines)
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}